@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+resource "daw_notebook_content" "getting_started" {
+
+  repository_id = "getting-started"
+  display_name  = "Getting started.ipynb"
+  source        = "${path.module}/getting-started.ipynb"
+
+  labels = {
+    team = "analytics"
+  }
+}
+
+output "commit_sha" {
+  value = daw_notebook_content.getting_started.commit_sha
+}
@@ -9,6 +9,14 @@ terraform {
 provider "daw" {
   project  = "gamma-priceline-playground"
   location = "australia-southeast1"
+
+  default_labels = {
+    "managed-by" = "terraform"
+  }
+
+  ignore_labels = {
+    key_prefixes = ["goog-"]
+  }
 }
 
 resource "daw_notebook" "basic_template" {
@@ -0,0 +1,43 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+resource "daw_notebook" "standard" {
+  display_name = "Standard runtime template"
+
+  machine_spec = {
+    machine_type = "e2-standard-4"
+  }
+
+  network_spec = {
+    network                = "projects/1019340507365/global/networks/default"
+    enable_internet_access = true
+  }
+
+  data_persistent_disk_spec = {
+    disk_type    = "pd-standard"
+    disk_size_gb = "50"
+  }
+}
+
+# pointing this at another template moves the default without replacing
+# either template, a second daw_notebook_default (or a daw_notebook with
+# is_default = true) in the location fails the plan. Leave is_default unset on
+# daw_notebook.standard, the two are mutually exclusive
+resource "daw_notebook_default" "location_default" {
+  notebook_runtime_template = daw_notebook.standard.name
+}
+
+# import {
+#   to = daw_notebook_default.location_default
+#   id = "projects/gamma-priceline-playground/locations/australia-southeast1"
+# }
@@ -0,0 +1,74 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+resource "daw_notebook" "batch" {
+
+  display_name = "Batch runtime template"
+
+  machine_spec = {
+    machine_type = "e2-standard-4"
+  }
+
+  network_spec = {
+    network                = "projects/1019340507365/global/networks/default"
+    enable_internet_access = true
+  }
+
+  data_persistent_disk_spec = {
+    disk_type    = "pd-standard"
+    disk_size_gb = "100"
+  }
+}
+
+resource "daw_notebook_execution_job" "train" {
+
+  display_name              = "Train model"
+  notebook_runtime_template = daw_notebook.batch.name
+
+  gcs_notebook_source = {
+    uri = "gs://gamma-priceline-notebooks/train.ipynb"
+  }
+
+  gcs_output_uri    = "gs://gamma-priceline-notebooks/output"
+  service_account   = "pipelines@gamma-priceline-playground.iam.gserviceaccount.com"
+  execution_timeout = "3600s"
+
+  labels = {
+    pipeline = "train"
+  }
+}
+
+output "train_output" {
+  value = daw_notebook_execution_job.train.output_uri
+}
+
+resource "daw_notebook_schedule" "nightly" {
+
+  display_name             = "Nightly feature engineering"
+  cron                     = "TZ=Australia/Melbourne 0 2 * * *"
+  max_concurrent_run_count = 1
+  paused                   = false
+
+  notebook_execution_job = {
+    display_name              = "Feature engineering"
+    notebook_runtime_template = daw_notebook.batch.name
+
+    gcs_notebook_source = {
+      uri = "gs://gamma-priceline-notebooks/features.ipynb"
+    }
+
+    gcs_output_uri    = "gs://gamma-priceline-notebooks/output"
+    service_account   = "pipelines@gamma-priceline-playground.iam.gserviceaccount.com"
+    execution_timeout = "7200s"
+  }
+}
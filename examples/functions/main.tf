@@ -0,0 +1,46 @@
+terraform {
+  # provider functions need Terraform 1.8 or later
+  required_version = ">= 1.8"
+
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+resource "daw_notebook" "template" {
+  display_name = "Template with a two hour idle timeout"
+
+  machine_spec = {
+    machine_type = "e2-standard-4"
+  }
+
+  data_persistent_disk_spec = {
+    disk_type    = "pd-standard"
+    disk_size_gb = "10"
+  }
+
+  idle_shutdown_config = {
+    # "7200s"
+    idle_timeout = provider::daw::duration_seconds("2h")
+  }
+}
+
+locals {
+  # instead of split("/", daw_notebook.template.name)
+  template = provider::daw::parse_template_name(daw_notebook.template.name)
+}
+
+output "template_id" {
+  value = local.template.id
+}
+
+output "template_in_other_location" {
+  value = provider::daw::template_name(local.template.project, "us-central1", local.template.id)
+}
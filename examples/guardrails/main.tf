@@ -0,0 +1,58 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+
+  default_labels = {
+    cost-centre = "analytics"
+  }
+
+  guardrails = {
+    require_kms_key           = true
+    allowed_machine_types     = ["e2-standard-*", "n1-standard-*"]
+    allowed_accelerator_types = ["NVIDIA_TESLA_T4"]
+    max_accelerator_count     = 2
+    forbid_internet_access    = true
+    required_labels           = ["cost-centre", "owner"]
+    max_disk_size_gb          = 500
+    require_idle_shutdown     = true
+  }
+}
+
+resource "daw_notebook" "compliant" {
+
+  display_name = "Compliant runtime template"
+  kms_key_name = "projects/gamma-priceline-playground/locations/australia-southeast1/keyRings/daw/cryptoKeys/notebooks"
+
+  machine_spec = {
+    machine_type      = "n1-standard-4"
+    accelerator_type  = "NVIDIA_TESLA_T4"
+    accelerator_count = 1
+  }
+
+  network_spec = {
+    network                = "projects/1019340507365/global/networks/default"
+    subnetwork             = "projects/1019340507365/regions/australia-southeast1/subnetworks/default"
+    enable_internet_access = false
+  }
+
+  data_persistent_disk_spec = {
+    disk_type    = "pd-standard"
+    disk_size_gb = "100"
+  }
+
+  idle_shutdown_config = {
+    idle_timeout = "3600s"
+  }
+
+  labels = {
+    owner = "data-platform"
+  }
+}
@@ -0,0 +1,44 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+data "daw_notebook" "prod" {
+  label_selector = {
+    "environment" = "prod"
+  }
+}
+
+resource "daw_notebook_iam_binding" "analysts" {
+  notebook_runtime_template = data.daw_notebook.prod.notebooks[0].name
+  role                      = "roles/aiplatform.notebookRuntimeUser"
+  members                   = ["group:analysts@example.com"]
+}
+
+resource "daw_notebook_iam_member" "contractor" {
+  notebook_runtime_template = data.daw_notebook.prod.notebooks[0].name
+  role                      = "roles/aiplatform.notebookRuntimeUser"
+  member                    = "user:contractor@example.com"
+
+  condition = {
+    title      = "expires-end-of-year"
+    expression = "request.time < timestamp(\"2027-01-01T00:00:00Z\")"
+  }
+}
+
+data "daw_notebook_iam_policy" "prod" {
+  notebook_runtime_template = data.daw_notebook.prod.notebooks[0].name
+  depends_on                = [daw_notebook_iam_binding.analysts, daw_notebook_iam_member.contractor]
+}
+
+output "policy" {
+  value = jsondecode(data.daw_notebook_iam_policy.prod.policy_data)
+}
@@ -0,0 +1,35 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+data "daw_machine_types" "available" {}
+
+locals {
+  # an n1 with at least 8 vCPUs, n1 takes the T4, P4, P100 and V100
+  n1_machine = [
+    for m in data.daw_machine_types.available.machine_types : m.name
+    if m.family == "n1" && m.guest_cpus >= 8
+  ][0]
+
+  t4 = [
+    for a in data.daw_machine_types.available.accelerator_types : a
+    if a.name == "NVIDIA_TESLA_T4"
+  ]
+}
+
+output "machine_type" {
+  value = local.n1_machine
+}
+
+output "t4_maximum_count" {
+  value = length(local.t4) > 0 ? local.t4[0].maximum_count : 0
+}
@@ -0,0 +1,74 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+
+  preset {
+    name = "small_cpu"
+
+    machine_spec = {
+      machine_type = "e2-standard-4"
+    }
+
+    data_persistent_disk_spec = {
+      disk_type    = "pd-standard"
+      disk_size_gb = "50"
+    }
+
+    idle_shutdown_config = {
+      idle_timeout = "3600s"
+    }
+  }
+
+  preset {
+    name = "t4_gpu"
+
+    machine_spec = {
+      machine_type      = "n1-standard-8"
+      accelerator_type  = "NVIDIA_TESLA_T4"
+      accelerator_count = 1
+    }
+
+    data_persistent_disk_spec = {
+      disk_type    = "pd-ssd"
+      disk_size_gb = "100"
+    }
+
+    idle_shutdown_config = {
+      idle_timeout = "1800s"
+    }
+  }
+}
+
+resource "daw_notebook" "small" {
+  display_name = "Small CPU runtime"
+  preset       = "small_cpu"
+
+  network_spec = {
+    network                = "projects/1019340507365/global/networks/default"
+    enable_internet_access = true
+  }
+}
+
+# attributes set on the resource win over the preset, the plan shows the
+# resolved machine_spec (n1-highmem-8 with one T4)
+resource "daw_notebook" "t4_highmem" {
+  display_name = "T4 runtime with more memory"
+  preset       = "t4_gpu"
+
+  machine_spec = {
+    machine_type = "n1-highmem-8"
+  }
+
+  network_spec = {
+    network                = "projects/1019340507365/global/networks/default"
+    enable_internet_access = true
+  }
+}
@@ -0,0 +1,55 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+resource "daw_notebook" "analyst" {
+
+  display_name = "Analyst runtime template"
+
+  machine_spec = {
+    machine_type = "e2-standard-4"
+  }
+
+  network_spec = {
+    network                = "projects/1019340507365/global/networks/default"
+    enable_internet_access = true
+  }
+
+  data_persistent_disk_spec = {
+    disk_type    = "pd-standard"
+    disk_size_gb = "100"
+  }
+
+  idle_shutdown_config = {
+    idle_timeout = "3600s"
+  }
+}
+
+resource "daw_notebook_runtime" "analyst" {
+  notebook_runtime_template = daw_notebook.analyst.name
+  runtime_user              = "analyst@example.com"
+  display_name              = "analyst-runtime"
+  desired_state             = "STOPPED"
+}
+
+output "proxy_uri" {
+  value = daw_notebook_runtime.analyst.proxy_uri
+}
+
+data "daw_notebook_runtimes" "analyst" {
+  notebook_runtime_template = daw_notebook.analyst.name
+  depends_on                = [daw_notebook_runtime.analyst]
+}
+
+output "analyst_runtimes" {
+  value = [for r in data.daw_notebook_runtimes.analyst.runtimes : "${r.runtime_user}: ${r.runtime_state}"]
+}
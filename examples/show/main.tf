@@ -15,4 +15,14 @@ data "daw_notebook" "example" {}
 
 output "my_notebooks" {
   value = data.daw_notebook.example
-}
\ No newline at end of file
+}
+data "daw_notebook" "prod" {
+  label_selector = {
+    "environment" = "prod"
+  }
+  sort_by = "display_name"
+}
+
+output "prod_notebooks" {
+  value = data.daw_notebook.prod.notebooks
+}
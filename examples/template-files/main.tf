@@ -0,0 +1,31 @@
+terraform {
+  # provider functions need Terraform 1.8 or later
+  required_version = ">= 1.8"
+
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+locals {
+  # errors in the document are reported with their line, e.g.
+  # "line 5: Accelerator type not supported by machine type: ..."
+  gpu = provider::daw::decode_template(file("${path.module}/templates/gpu.yaml"))
+}
+
+resource "daw_notebook" "gpu_template" {
+  display_name              = local.gpu.display_name
+  description               = local.gpu.description
+  machine_spec              = local.gpu.machine_spec
+  data_persistent_disk_spec = local.gpu.data_persistent_disk_spec
+  network_spec              = local.gpu.network_spec
+  idle_shutdown_config      = local.gpu.idle_shutdown_config
+  labels                    = local.gpu.labels
+}
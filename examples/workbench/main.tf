@@ -0,0 +1,61 @@
+terraform {
+  required_providers {
+    daw = {
+      source = "stella.com/anz/daw"
+    }
+  }
+}
+
+provider "daw" {
+  project  = "gamma-priceline-playground"
+  location = "australia-southeast1"
+}
+
+resource "daw_workbench_instance" "analyst" {
+
+  instance_id  = "analyst-workbench"
+  zone         = "australia-southeast1-a"
+  machine_type = "n1-standard-4"
+
+  accelerator_config = {
+    type       = "NVIDIA_TESLA_T4"
+    core_count = 1
+  }
+
+  boot_disk = {
+    disk_type    = "PD_BALANCED"
+    disk_size_gb = 150
+  }
+
+  data_disk = {
+    disk_type    = "PD_STANDARD"
+    disk_size_gb = 200
+  }
+
+  network_interface = {
+    network = "projects/gamma-priceline-playground/global/networks/default"
+    subnet  = "projects/gamma-priceline-playground/regions/australia-southeast1/subnetworks/default"
+  }
+
+  disable_public_ip = true
+  service_account   = "analyst@gamma-priceline-playground.iam.gserviceaccount.com"
+
+  shielded_instance_config = {
+    enable_secure_boot = true
+    enable_vtpm        = true
+  }
+
+  metadata = {
+    idle-timeout-seconds = "10800"
+  }
+
+  labels = {
+    team = "analytics"
+  }
+
+  desired_state = "ACTIVE"
+}
+
+output "proxy_uri" {
+  value = daw_workbench_instance.analyst.proxy_uri
+}
@@ -0,0 +1,280 @@
+// Package export writes existing runtime templates out as daw_notebook
+// resources with matching import blocks, so templates made by hand can be
+// brought under Terraform in bulk.
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+)
+
+// Options select the templates to export and where they go
+type Options struct {
+	// OutputDir receives a <resource name>.tf file per template
+	OutputDir string
+
+	// NameRegexp, when set, has to match the display name or id of a template
+	NameRegexp *regexp.Regexp
+
+	// Labels a template has to carry with the same values
+	Labels map[string]string
+}
+
+// Run lists the templates of the client's project and location and writes those
+// matching the options, returning the paths of the files written. Existing
+// files are never overwritten, nothing is written when one is in the way.
+func Run(client *gcp.NotebookClient, opts Options) ([]string, error) {
+
+	notebooks, err := client.GetNotebooks()
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := render(notebooks.NotebookRuntimeTemplates, opts)
+
+	for _, output := range outputs {
+		if _, err := os.Stat(filepath.Join(opts.OutputDir, output.file)); err == nil {
+			return nil, fmt.Errorf("%s already exists, export into an empty directory", filepath.Join(opts.OutputDir, output.file))
+		}
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var files []string
+
+	for _, output := range outputs {
+		file := filepath.Join(opts.OutputDir, output.file)
+		if err := writeNewFile(file, output.contents); err != nil {
+			return files, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// output is a file to write, relative to the output directory
+type output struct {
+	file     string
+	contents []byte
+}
+
+// render turns the templates matching the options into one file each, named
+// after its resource
+func render(templates []gcp.NotebookRuntimeTemplate, opts Options) []output {
+
+	var outputs []output
+	used := make(map[string]bool)
+
+	for _, template := range templates {
+
+		if !opts.matches(template) {
+			continue
+		}
+
+		resourceName := uniqueName(resourceName(template), used)
+
+		outputs = append(outputs, output{file: resourceName + ".tf", contents: Template(template, resourceName)})
+	}
+	return outputs
+}
+
+// writeNewFile writes contents to file, failing when it exists
+func writeNewFile(file string, contents []byte) error {
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(contents); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (o Options) matches(template gcp.NotebookRuntimeTemplate) bool {
+
+	if o.NameRegexp != nil {
+		id := filepath.Base(value(template.Name))
+		if !o.NameRegexp.MatchString(value(template.DisplayName)) && !o.NameRegexp.MatchString(id) {
+			return false
+		}
+	}
+
+	for key, want := range o.Labels {
+		if template.Labels == nil {
+			return false
+		}
+		if got, ok := (*template.Labels)[key]; !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// resourceName turns the display name (or the id when there is none) into a
+// valid Terraform resource name
+func resourceName(template gcp.NotebookRuntimeTemplate) string {
+
+	name := value(template.DisplayName)
+	if name == "" {
+		name = filepath.Base(value(template.Name))
+	}
+
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "template_" + name
+	}
+	return name
+}
+
+// uniqueName suffixes names already handed out, display names need not be unique
+func uniqueName(name string, used map[string]bool) string {
+
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// Template renders the daw_notebook resource and import block of a template
+func Template(template gcp.NotebookRuntimeTemplate, resourceName string) []byte {
+
+	var b hclBuilder
+
+	b.line("import {")
+	b.attribute(1, "to", "daw_notebook."+resourceName)
+	b.attribute(1, "id", quote(value(template.Name)))
+	b.line("}")
+	b.line("")
+
+	b.line(fmt.Sprintf("resource %q %q {", "daw_notebook", resourceName))
+
+	if project, location, _, err := gcp.ParseTemplateName(value(template.Name)); err == nil {
+		b.attribute(1, "project", quote(project))
+		b.attribute(1, "location", quote(location))
+	}
+	b.attribute(1, "display_name", quote(value(template.DisplayName)))
+	if template.Description != nil && *template.Description != "" {
+		b.attribute(1, "description", quote(*template.Description))
+	}
+	if template.IsDefault != nil && *template.IsDefault {
+		b.attribute(1, "is_default", "true")
+	}
+	if template.EncryptionSpec != nil && template.EncryptionSpec.KmsKeyName != nil {
+		b.attribute(1, "kms_key_name", quote(*template.EncryptionSpec.KmsKeyName))
+	}
+
+	if spec := template.MachineSpec; spec != nil {
+		b.open(1, "machine_spec")
+		b.attribute(2, "machine_type", quote(value(spec.MachineType)))
+		if spec.AcceleratorType != nil && *spec.AcceleratorType != "" && *spec.AcceleratorType != "ACCELERATOR_TYPE_UNSPECIFIED" {
+			b.attribute(2, "accelerator_type", quote(*spec.AcceleratorType))
+		}
+		if spec.AcceleratorCount != nil && *spec.AcceleratorCount > 0 {
+			b.attribute(2, "accelerator_count", strconv.FormatInt(*spec.AcceleratorCount, 10))
+		}
+		b.close(1)
+	}
+
+	if spec := template.DataPersistentDiskSpec; spec != nil {
+		b.open(1, "data_persistent_disk_spec")
+		b.attribute(2, "disk_type", quote(value(spec.DiskType)))
+		b.attribute(2, "disk_size_gb", quote(value(spec.DiskSizeGb)))
+		b.close(1)
+	}
+
+	if spec := template.NetworkSpec; spec != nil {
+		b.open(1, "network_spec")
+		b.attribute(2, "enable_internet_access", strconv.FormatBool(spec.EnableInternetAccess != nil && *spec.EnableInternetAccess))
+		b.attribute(2, "network", quote(value(spec.Network)))
+		if spec.Subnetwork != nil && *spec.Subnetwork != "" {
+			b.attribute(2, "subnetwork", quote(*spec.Subnetwork))
+		}
+		b.close(1)
+	}
+
+	if config := template.IdleShutdownConfig; config != nil {
+		b.open(1, "idle_shutdown_config")
+		if config.IdleTimeout != nil {
+			b.attribute(2, "idle_timeout", quote(*config.IdleTimeout))
+		}
+		if config.IdleShutdownDisabled != nil && *config.IdleShutdownDisabled {
+			b.attribute(2, "idle_shutdown_disabled", "true")
+		}
+		b.close(1)
+	}
+
+	if template.Labels != nil && len(*template.Labels) > 0 {
+
+		keys := make([]string, 0, len(*template.Labels))
+		for key := range *template.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b.open(1, "labels")
+		for _, key := range keys {
+			b.attribute(2, quote(key), quote((*template.Labels)[key]))
+		}
+		b.close(1)
+	}
+
+	b.line("}")
+
+	return []byte(b.String())
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// quote renders s as an HCL string, template sequences are escaped so they
+// come out literally
+func quote(s string) string {
+
+	var b strings.Builder
+
+	b.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			b.WriteRune(r)
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
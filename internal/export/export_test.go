@@ -0,0 +1,136 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// TestRender compares the files rendered for each list response in testdata
+// with its golden file, which holds them one after the other
+func TestRender(t *testing.T) {
+
+	inputs, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range inputs {
+
+		name := strings.TrimSuffix(filepath.Base(input), ".json")
+
+		t.Run(name, func(t *testing.T) {
+
+			content, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var templates gcp.ListNotebookRuntimeTemplatesResult
+			if err := json.Unmarshal(content, &templates); err != nil {
+				t.Fatal(err)
+			}
+
+			var got bytes.Buffer
+			for _, output := range render(templates.NotebookRuntimeTemplates, Options{}) {
+				fmt.Fprintf(&got, "# %s\n%s", output.file, output.contents)
+			}
+
+			golden := filepath.Join("testdata", name+".golden")
+
+			if *update {
+				if err := os.WriteFile(golden, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != string(want) {
+				t.Fatalf("%s doesn't match, run go test ./internal/export -update to accept the changes\n\ngot:\n%s\nwant:\n%s", golden, got.String(), want)
+			}
+		})
+	}
+}
+
+func TestResourceName(t *testing.T) {
+
+	tests := []struct {
+		displayName string
+		name        string
+		want        string
+	}{
+		{displayName: "Team GPU (T4)", want: "team_gpu_t4"},
+		{displayName: "2024 analysis", want: "template_2024_analysis"},
+		{displayName: "---", want: "template_"},
+		{name: "projects/p/locations/l/notebookRuntimeTemplates/123", want: "template_123"},
+		{name: "projects/p/locations/l/notebookRuntimeTemplates/abc", want: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+
+			template := gcp.NotebookRuntimeTemplate{}
+			if tt.displayName != "" {
+				template.DisplayName = &tt.displayName
+			}
+			if tt.name != "" {
+				template.Name = &tt.name
+			}
+
+			if got := resourceName(template); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+
+	used := make(map[string]bool)
+
+	var got []string
+	for _, name := range []string{"gpu", "gpu", "cpu", "gpu", "gpu_2"} {
+		got = append(got, uniqueName(name, used))
+	}
+
+	want := []string{"gpu", "gpu_2", "cpu", "gpu_3", "gpu_2_2"}
+
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestQuote(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: `plain`, want: `"plain"`},
+		{value: `say "hi" \ bye`, want: `"say \"hi\" \\ bye"`},
+		{value: "two\nlines\ttabbed", want: `"two\nlines\ttabbed"`},
+		{value: `${var.name}`, want: `"$${var.name}"`},
+		{value: `%{if x}y%{endif}`, want: `"%%{if x}y%%{endif}"`},
+		{value: `$5 and 100%`, want: `"$5 and 100%"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := quote(tt.value); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
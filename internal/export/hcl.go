@@ -0,0 +1,55 @@
+package export
+
+import "strings"
+
+// hclBuilder writes HCL laid out the way terraform fmt would, the equals
+// signs of consecutive attributes are aligned
+type hclBuilder struct {
+	strings.Builder
+
+	// attributes waiting to be aligned with the ones that follow
+	pending []hclAttribute
+}
+
+type hclAttribute struct {
+	indent int
+	name   string
+	value  string
+}
+
+func (b *hclBuilder) attribute(indent int, name string, value string) {
+	b.pending = append(b.pending, hclAttribute{indent: indent, name: name, value: value})
+}
+
+// open starts an object attribute, e.g. machine_spec = {
+func (b *hclBuilder) open(indent int, name string) {
+	b.line(strings.Repeat("  ", indent) + name + " = {")
+}
+
+func (b *hclBuilder) close(indent int) {
+	b.line(strings.Repeat("  ", indent) + "}")
+}
+
+func (b *hclBuilder) line(line string) {
+	b.flush()
+	b.WriteString(line)
+	b.WriteString("\n")
+}
+
+func (b *hclBuilder) flush() {
+
+	width := 0
+	for _, attribute := range b.pending {
+		width = max(width, len(attribute.name))
+	}
+
+	for _, attribute := range b.pending {
+		b.WriteString(strings.Repeat("  ", attribute.indent))
+		b.WriteString(attribute.name)
+		b.WriteString(strings.Repeat(" ", width-len(attribute.name)))
+		b.WriteString(" = ")
+		b.WriteString(attribute.value)
+		b.WriteString("\n")
+	}
+	b.pending = nil
+}
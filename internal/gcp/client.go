@@ -0,0 +1,181 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// Config holds the settings shared by every client
+type Config struct {
+	// Credentials is either the path to or the contents of a service account
+	// key or external account JSON file
+	Credentials string
+
+	// AccessToken is used as-is instead of looking up credentials
+	AccessToken string
+
+	// ImpersonateServiceAccount is the email of the service account to
+	// impersonate, optionally through a chain of delegates
+	ImpersonateServiceAccount          string
+	ImpersonateServiceAccountDelegates []string
+
+	// UserProjectOverride sends the x-goog-user-project header so quota and
+	// billing are charged to BillingProject, or the resource project when
+	// no billing project is set
+	BillingProject      string
+	UserProjectOverride bool
+
+	// UserAgent is sent with every request
+	UserAgent string
+
+	// VertexAIEndpoint, NotebooksEndpoint, DataformEndpoint and ComputeEndpoint
+	// replace the default API endpoints, "{{location}}" is replaced with the
+	// location of the client
+	VertexAIEndpoint  string
+	NotebooksEndpoint string
+	DataformEndpoint  string
+	ComputeEndpoint   string
+
+	// MaxRetries is how often a request failing with a transient error
+	// (429, 502, 503 or 504) is retried, DefaultMaxRetries when nil
+	MaxRetries *int
+}
+
+func (c Config) maxRetries() int {
+
+	if c.MaxRetries == nil {
+		return DefaultMaxRetries
+	}
+	return *c.MaxRetries
+}
+
+// userProject is the value of the x-goog-user-project header for requests
+// against the project, empty when the header shouldn't be sent
+func (c Config) userProject(projectID string) string {
+
+	if !c.UserProjectOverride {
+		return ""
+	}
+	if c.BillingProject != "" {
+		return c.BillingProject
+	}
+	return projectID
+}
+
+// ClientFactory hands out clients for any project and location. The
+// credentials are shared and a single client is kept per project/location pair.
+type ClientFactory struct {
+	config      Config
+	tokenSource oauth2.TokenSource
+
+	mu        sync.Mutex
+	notebooks map[string]*NotebookClient
+	workbench map[string]*WorkbenchClient
+	dataform  map[string]*DataformClient
+	compute   map[string]*ComputeClient
+}
+
+func NewClientFactory(config Config) (*ClientFactory, error) {
+
+	ctx := context.Background()
+
+	tokenSource, err := config.tokenSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// fail early rather than on the first request
+	if _, err := tokenSource.Token(); err != nil {
+		return nil, err
+	}
+
+	return &ClientFactory{
+		config:      config,
+		tokenSource: tokenSource,
+		notebooks:   make(map[string]*NotebookClient),
+		workbench:   make(map[string]*WorkbenchClient),
+		dataform:    make(map[string]*DataformClient),
+		compute:     make(map[string]*ComputeClient),
+	}, nil
+}
+
+// NotebookClient returns the (cached) client for the project and location
+func (f *ClientFactory) NotebookClient(projectID string, location string) *NotebookClient {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%s", projectID, location)
+
+	client, ok := f.notebooks[key]
+	if !ok {
+		endpoint := serviceEndpoint
+		if f.config.VertexAIEndpoint != "" {
+			endpoint = f.config.VertexAIEndpoint
+		}
+		client = newNotebookClient(projectID, location, f.newTransport(endpoint, projectID, location))
+		f.notebooks[key] = client
+	}
+	return client
+}
+
+// WorkbenchClient returns the (cached) Workbench client for the project and zone
+func (f *ClientFactory) WorkbenchClient(projectID string, zone string) *WorkbenchClient {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%s", projectID, zone)
+
+	client, ok := f.workbench[key]
+	if !ok {
+		endpoint := workbenchEndpoint
+		if f.config.NotebooksEndpoint != "" {
+			endpoint = f.config.NotebooksEndpoint
+		}
+		client = newWorkbenchClient(projectID, zone, f.newTransport(endpoint, projectID, zone))
+		f.workbench[key] = client
+	}
+	return client
+}
+
+// DataformClient returns the (cached) Dataform client for the project and location
+func (f *ClientFactory) DataformClient(projectID string, location string) *DataformClient {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%s", projectID, location)
+
+	client, ok := f.dataform[key]
+	if !ok {
+		endpoint := dataformEndpoint
+		if f.config.DataformEndpoint != "" {
+			endpoint = f.config.DataformEndpoint
+		}
+		client = newDataformClient(projectID, location, f.newTransport(endpoint, projectID, location))
+		f.dataform[key] = client
+	}
+	return client
+}
+
+// ComputeClient returns the (cached) Compute Engine client for the project
+func (f *ClientFactory) ComputeClient(projectID string) *ComputeClient {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	client, ok := f.compute[projectID]
+	if !ok {
+		endpoint := computeEndpoint
+		if f.config.ComputeEndpoint != "" {
+			endpoint = f.config.ComputeEndpoint
+		}
+		client = newComputeClient(projectID, f.newTransport(endpoint, projectID, ""))
+		f.compute[projectID] = client
+	}
+	return client
+}
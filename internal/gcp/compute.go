@@ -0,0 +1,118 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Compute Engine describes the machine and accelerator types of each zone
+const computeEndpoint = "https://compute.googleapis.com/compute/v1"
+
+// ComputeClient reads Compute Engine metadata for a single project
+type ComputeClient struct {
+	transport
+	parent string
+}
+
+func newComputeClient(projectID string, transport transport) *ComputeClient {
+
+	return &ComputeClient{
+		transport: transport,
+		parent:    fmt.Sprintf("%s/projects/%s", transport.endpoint, projectID),
+	}
+}
+
+// GetMachineTypes lists the machine types of every zone in the region
+func (cc *ComputeClient) GetMachineTypes(region string) ([]MachineType, error) {
+
+	var machineTypes []MachineType
+
+	err := cc.aggregated("machineTypes", region, func(items json.RawMessage) error {
+
+		var scoped struct {
+			MachineTypes []MachineType `json:"machineTypes"`
+		}
+		if err := json.Unmarshal(items, &scoped); err != nil {
+			return err
+		}
+		machineTypes = append(machineTypes, scoped.MachineTypes...)
+		return nil
+	})
+
+	return machineTypes, err
+}
+
+// GetAcceleratorTypes lists the accelerator types of every zone in the region
+func (cc *ComputeClient) GetAcceleratorTypes(region string) ([]AcceleratorType, error) {
+
+	var acceleratorTypes []AcceleratorType
+
+	err := cc.aggregated("acceleratorTypes", region, func(items json.RawMessage) error {
+
+		var scoped struct {
+			AcceleratorTypes []AcceleratorType `json:"acceleratorTypes"`
+		}
+		if err := json.Unmarshal(items, &scoped); err != nil {
+			return err
+		}
+		acceleratorTypes = append(acceleratorTypes, scoped.AcceleratorTypes...)
+		return nil
+	})
+
+	return acceleratorTypes, err
+}
+
+// aggregated pages through an aggregated list, calling add with the items of
+// each zone in the region (in zone order)
+func (cc *ComputeClient) aggregated(collection string, region string, add func(items json.RawMessage) error) error {
+
+	pageToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("returnPartialSuccess", "true")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		body, err := cc.curl(http.MethodGet, fmt.Sprintf("%s/aggregated/%s?%s", cc.parent, collection, query.Encode()), nil)
+
+		if err != nil {
+			return err
+		}
+
+		var page struct {
+			Items         map[string]json.RawMessage `json:"items"`
+			NextPageToken string                     `json:"nextPageToken"`
+		}
+
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+
+		// items are keyed by scope, e.g. "zones/australia-southeast1-a"
+		var scopes []string
+		for scope := range page.Items {
+			if strings.HasPrefix(path.Base(scope), region+"-") {
+				scopes = append(scopes, scope)
+			}
+		}
+		sort.Strings(scopes)
+
+		for _, scope := range scopes {
+			if err := add(page.Items[scope]); err != nil {
+				return err
+			}
+		}
+
+		if page.NextPageToken == "" {
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
@@ -0,0 +1,158 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const iamCredentialsEndpoint = "https://iamcredentials.googleapis.com/v1"
+
+// tokenSource builds the token source described by the config, in order of
+// precedence: an access token, explicit credentials, then the application
+// default credentials. The result is wrapped when impersonation is requested.
+func (c Config) tokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+
+	var ts oauth2.TokenSource
+
+	switch {
+	case c.AccessToken != "":
+		ts = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: c.AccessToken})
+
+	case c.Credentials != "":
+		contents, err := credentialsContents(c.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		creds, err := google.CredentialsFromJSON(ctx, contents, scopes)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse credentials: %w", err)
+		}
+		ts = creds.TokenSource
+
+	default:
+		creds, err := google.FindDefaultCredentials(ctx, scopes)
+		if err != nil {
+			return nil, err
+		}
+		ts = creds.TokenSource
+	}
+
+	if c.ImpersonateServiceAccount != "" {
+		ts = oauth2.ReuseTokenSource(nil, &impersonatedTokenSource{
+			source:         ts,
+			serviceAccount: c.ImpersonateServiceAccount,
+			delegates:      c.ImpersonateServiceAccountDelegates,
+			userAgent:      c.UserAgent,
+		})
+	}
+	return ts, nil
+}
+
+// credentialsContents accepts either the path to a credentials file or the
+// JSON content itself
+func credentialsContents(credentials string) ([]byte, error) {
+
+	if strings.HasPrefix(strings.TrimSpace(credentials), "{") {
+		return []byte(credentials), nil
+	}
+
+	contents, err := os.ReadFile(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("could not read credentials file: %w", err)
+	}
+	return contents, nil
+}
+
+// impersonatedTokenSource exchanges the source token for a token of the
+// service account using the IAM Credentials API
+type impersonatedTokenSource struct {
+	source         oauth2.TokenSource
+	serviceAccount string
+	delegates      []string
+	userAgent      string
+}
+
+type generateAccessTokenRequest struct {
+	Delegates []string `json:"delegates,omitempty"`
+	Scope     []string `json:"scope"`
+	Lifetime  string   `json:"lifetime"`
+}
+
+type generateAccessTokenResponse struct {
+	AccessToken string `json:"accessToken"`
+	ExpireTime  string `json:"expireTime"`
+}
+
+func (i *impersonatedTokenSource) Token() (*oauth2.Token, error) {
+
+	token, err := i.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	request := generateAccessTokenRequest{
+		Scope:    []string{scopes},
+		Lifetime: "3600s",
+	}
+	for _, delegate := range i.delegates {
+		request.Delegates = append(request.Delegates, serviceAccountResource(delegate))
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/%s:generateAccessToken", iamCredentialsEndpoint, serviceAccountResource(i.serviceAccount))
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	req.Header.Set("Content-Type", "application/json")
+
+	if i.userAgent != "" {
+		req.Header.Set("User-Agent", i.userAgent)
+	}
+
+	body, err := do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not impersonate %s: %w", i.serviceAccount, err)
+	}
+
+	var response generateAccessTokenResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	expiry, err := time.Parse(time.RFC3339, response.ExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: response.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      expiry,
+	}, nil
+}
+
+// serviceAccountResource expands an email into the resource name expected by
+// the IAM Credentials API
+func serviceAccountResource(serviceAccount string) string {
+
+	if strings.HasPrefix(serviceAccount, "projects/") {
+		return serviceAccount
+	}
+	return fmt.Sprintf("projects/-/serviceAccounts/%s", serviceAccount)
+}
@@ -0,0 +1,160 @@
+package gcp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Dataform hosts the repositories Colab Enterprise keeps notebooks in
+const dataformEndpoint = "https://dataform.googleapis.com/v1beta1"
+
+// ColabRepositoryLabel marks a Dataform repository as holding a Colab Enterprise notebook
+const ColabRepositoryLabel = "ui.vertex-ai-colab"
+
+// DataformClient manages Dataform repositories in a single project and location
+type DataformClient struct {
+	transport
+	parent string
+}
+
+func newDataformClient(projectID string, location string, transport transport) *DataformClient {
+
+	return &DataformClient{
+		transport: transport,
+		parent:    fmt.Sprintf("%s/projects/%s/locations/%s", transport.endpoint, projectID, location),
+	}
+}
+
+func (dc *DataformClient) CreateRepository(repositoryID string, repository *DataformRepository) (*DataformRepository, error) {
+
+	payload, err := json.Marshal(repository)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("repositoryId", repositoryID)
+
+	body, err := dc.curl(http.MethodPost, fmt.Sprintf("%s/repositories?%s", dc.parent, query.Encode()), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	var created DataformRepository
+	err = json.Unmarshal(body, &created)
+
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (dc *DataformClient) GetRepository(name string) (*DataformRepository, error) {
+
+	body, err := dc.curl(http.MethodGet, fmt.Sprintf("%s/%s", dc.endpoint, name), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var repository DataformRepository
+	err = json.Unmarshal(body, &repository)
+
+	if err != nil {
+		return nil, err
+	}
+	return &repository, nil
+}
+
+// UpdateRepository patches the fields in updateMask (API field names, e.g. "labels")
+func (dc *DataformClient) UpdateRepository(repository *DataformRepository, updateMask string) (*DataformRepository, error) {
+
+	payload, err := json.Marshal(repository)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("updateMask", updateMask)
+
+	body, err := dc.curl(http.MethodPatch, fmt.Sprintf("%s/%s?%s", dc.endpoint, *repository.Name, query.Encode()), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	var updated DataformRepository
+	err = json.Unmarshal(body, &updated)
+
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteRepository deletes the repository along with its commits
+func (dc *DataformClient) DeleteRepository(name string) error {
+
+	_, err := dc.curl(http.MethodDelete, fmt.Sprintf("%s/%s?force=true", dc.endpoint, name), nil)
+	return err
+}
+
+// CommitFile writes contents to path on the default branch, returning the sha
+// of the new commit
+func (dc *DataformClient) CommitFile(name string, path string, contents []byte, author CommitAuthor, message string) (string, error) {
+
+	request := CommitRepositoryChangesRequest{
+		CommitMetadata: CommitMetadata{
+			Author:        author,
+			CommitMessage: message,
+		},
+		FileOperations: map[string]FileOperation{
+			path: {WriteFile: &WriteFile{Contents: base64.StdEncoding.EncodeToString(contents)}},
+		},
+	}
+
+	payload, err := json.Marshal(request)
+
+	if err != nil {
+		return "", err
+	}
+
+	body, err := dc.curl(http.MethodPost, fmt.Sprintf("%s/%s:commit", dc.endpoint, name), bytes.NewBuffer(payload))
+	if err != nil {
+		return "", err
+	}
+
+	var response CommitRepositoryChangesResponse
+	err = json.Unmarshal(body, &response)
+
+	if err != nil {
+		return "", err
+	}
+	return response.CommitSha, nil
+}
+
+// ReadFile returns the contents of path on the default branch
+func (dc *DataformClient) ReadFile(name string, path string) ([]byte, error) {
+
+	query := url.Values{}
+	query.Set("path", path)
+
+	body, err := dc.curl(http.MethodGet, fmt.Sprintf("%s/%s:readFile?%s", dc.endpoint, name, query.Encode()), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var response ReadRepositoryFileResponse
+	err = json.Unmarshal(body, &response)
+
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Contents)
+}
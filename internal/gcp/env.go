@@ -0,0 +1,37 @@
+package gcp
+
+import (
+	"os"
+	"strconv"
+)
+
+// ConfigFromEnv reads the same environment variables as the google provider,
+// the provider and the export command both start from it
+func ConfigFromEnv() Config {
+
+	config := Config{
+		Credentials:               multiEnvDefault("GOOGLE_CREDENTIALS", "GOOGLE_CLOUD_KEYFILE_JSON", "GCLOUD_KEYFILE_JSON"),
+		AccessToken:               os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+		ImpersonateServiceAccount: os.Getenv("GOOGLE_IMPERSONATE_SERVICE_ACCOUNT"),
+		BillingProject:            os.Getenv("GOOGLE_BILLING_PROJECT"),
+		VertexAIEndpoint:          os.Getenv("GOOGLE_VERTEX_AI_CUSTOM_ENDPOINT"),
+		NotebooksEndpoint:         os.Getenv("GOOGLE_NOTEBOOKS_CUSTOM_ENDPOINT"),
+		DataformEndpoint:          os.Getenv("GOOGLE_DATAFORM_CUSTOM_ENDPOINT"),
+		ComputeEndpoint:           os.Getenv("GOOGLE_COMPUTE_CUSTOM_ENDPOINT"),
+	}
+
+	if override, err := strconv.ParseBool(os.Getenv("USER_PROJECT_OVERRIDE")); err == nil {
+		config.UserProjectOverride = override
+	}
+	return config
+}
+
+// multiEnvDefault returns the value of the first environment variable set
+func multiEnvDefault(keys ...string) string {
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Job states reported by the API
+const (
+	JobStateSucceeded = "JOB_STATE_SUCCEEDED"
+	JobStateFailed    = "JOB_STATE_FAILED"
+	JobStateCancelled = "JOB_STATE_CANCELLED"
+	JobStateExpired   = "JOB_STATE_EXPIRED"
+)
+
+const executionJobPollInterval = 30 * time.Second
+
+// IsTerminalJobState reports whether a job in this state won't change anymore
+func IsTerminalJobState(state string) bool {
+	switch state {
+	case JobStateSucceeded, JobStateFailed, JobStateCancelled, JobStateExpired:
+		return true
+	}
+	return false
+}
+
+// CreateNotebookExecutionJob creates the job, returning once it has been
+// accepted. Use WaitForNotebookExecutionJob to wait for the run to finish.
+func (nc *NotebookClient) CreateNotebookExecutionJob(ctx context.Context, job *NotebookExecutionJob) (*NotebookExecutionJob, error) {
+
+	payload, err := json.Marshal(job)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := nc.curl(http.MethodPost, fmt.Sprintf("%s/notebookExecutionJobs", nc.parent), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	var created NotebookExecutionJob
+
+	err = nc.waitForOperation(ctx, body, &created)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if created.Name == nil {
+		return nil, fmt.Errorf("could not retrieve Name of newly created execution job")
+	}
+
+	return nc.GetNotebookExecutionJob(*created.Name)
+}
+
+func (nc *NotebookClient) GetNotebookExecutionJob(name string) (*NotebookExecutionJob, error) {
+
+	body, err := nc.curl(http.MethodGet, fmt.Sprintf("%s/%s", nc.endpoint, name), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var job NotebookExecutionJob
+	err = json.Unmarshal(body, &job)
+
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// WaitForNotebookExecutionJob polls the job until it reaches a terminal state,
+// giving up when ctx is cancelled
+func (nc *NotebookClient) WaitForNotebookExecutionJob(ctx context.Context, name string, timeout time.Duration) (*NotebookExecutionJob, error) {
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		job, err := nc.GetNotebookExecutionJob(name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if job.JobState != nil && IsTerminalJobState(*job.JobState) {
+			return job, nil
+		}
+
+		if time.Now().After(deadline) {
+			return job, fmt.Errorf("timed out waiting for execution job %s", name)
+		}
+		select {
+		case <-ctx.Done():
+			return job, fmt.Errorf("stopped waiting for execution job %s: %w", name, ctx.Err())
+		case <-time.After(executionJobPollInterval):
+		}
+	}
+}
+
+func (nc *NotebookClient) DeleteNotebookExecutionJob(ctx context.Context, name string) error {
+
+	body, err := nc.curl(http.MethodDelete, fmt.Sprintf("%s/%s", nc.endpoint, name), nil)
+
+	if err != nil {
+		return err
+	}
+	return nc.waitForOperation(ctx, body, nil)
+}
@@ -0,0 +1,87 @@
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// conditional role bindings require version 3 policies
+const iamPolicyVersion = 3
+
+// https://cloud.google.com/vertex-ai/docs/reference/rest/v1beta1/Policy
+type Policy struct {
+	Version  int       `json:"version,omitempty"`
+	Bindings []Binding `json:"bindings,omitempty"`
+	Etag     string    `json:"etag,omitempty"`
+}
+
+type Binding struct {
+	Role      string   `json:"role"`
+	Members   []string `json:"members"`
+	Condition *Expr    `json:"condition,omitempty"`
+}
+
+type Expr struct {
+	Expression  string `json:"expression"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type setIamPolicyRequest struct {
+	Policy *Policy `json:"policy"`
+}
+
+// GetIamPolicy returns the IAM policy of a resource, e.g. a runtime template
+func (nc *NotebookClient) GetIamPolicy(resource string) (*Policy, error) {
+
+	url := fmt.Sprintf("%s/%s:getIamPolicy?options.requestedPolicyVersion=%d", nc.endpoint, resource, iamPolicyVersion)
+	body, err := nc.curl(http.MethodPost, url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var policy Policy
+	err = json.Unmarshal(body, &policy)
+
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SetIamPolicy replaces the IAM policy of a resource. The etag of the policy
+// guards against concurrent changes, a conflict is reported as a 409.
+func (nc *NotebookClient) SetIamPolicy(resource string, policy *Policy) (*Policy, error) {
+
+	policy.Version = iamPolicyVersion
+
+	payload, err := json.Marshal(setIamPolicyRequest{Policy: policy})
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := nc.curl(http.MethodPost, fmt.Sprintf("%s/%s:setIamPolicy", nc.endpoint, resource), bytes.NewBuffer(payload))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var updated Policy
+	err = json.Unmarshal(body, &updated)
+
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// IsConflict reports whether err is a 409 response, e.g. a stale policy etag
+func IsConflict(err error) bool {
+	var respErr *ResponseError
+	return errors.As(err, &respErr) && respErr.Code == http.StatusConflict
+}
@@ -4,16 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-
-	"golang.org/x/oauth2/google"
+	"net/url"
+	"strings"
 )
 
 type NotebookClient struct {
-	url   string
-	token string
+	transport
+	url    string
+	parent string
 }
 
 type ResponseError struct {
@@ -25,44 +26,86 @@ func (e *ResponseError) Error() string {
 	return fmt.Sprintf("Error response status (%d): %s", e.Code, e.Message)
 }
 
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	var respErr *ResponseError
+	return errors.As(err, &respErr) && respErr.Code == http.StatusNotFound
+}
+
 const (
-	scopes          = "https://www.googleapis.com/auth/cloud-platform"
-	serviceEndpoint = "https://australia-southeast1-aiplatform.googleapis.com/v1beta1"
+	scopes = "https://www.googleapis.com/auth/cloud-platform"
+
+	// Vertex AI is served from a regional endpoint per location
+	serviceEndpoint = "https://{{location}}-aiplatform.googleapis.com/v1beta1"
 )
 
+// NewNotebookClient creates a client for a single project and location using
+// the application default credentials.
 func NewNotebookClient(projectID string, location string) (*NotebookClient, error) {
 
-	ctx := context.Background()
-
-	creds, err := google.FindDefaultCredentials(ctx, scopes)
+	factory, err := NewClientFactory(Config{})
 	if err != nil {
 		return nil, err
 	}
+	return factory.NotebookClient(projectID, location), nil
+}
 
-	token, err := creds.TokenSource.Token()
-	if err != nil {
-		return nil, err
-	}
+func newNotebookClient(projectID string, location string, transport transport) *NotebookClient {
+
+	parent := fmt.Sprintf("%s/projects/%s/locations/%s", transport.endpoint, projectID, location)
 
 	return &NotebookClient{
-		url:   fmt.Sprintf("%s/projects/%s/locations/%s/notebookRuntimeTemplates", serviceEndpoint, projectID, location),
-		token: token.AccessToken,
-	}, nil
+		transport: transport,
+		url:       fmt.Sprintf("%s/notebookRuntimeTemplates", parent),
+		parent:    parent,
+	}
 }
 
 func (n *NotebookClient) GetNotebooks() (*ListNotebookRuntimeTemplatesResult, error) {
+	return n.GetNotebooksWithFilter("")
+}
 
-	body, err := n.curl(http.MethodGet, n.url, nil)
-
-	if err != nil {
-		return nil, err
-	}
+// GetNotebooksWithFilter lists the templates matching the server-side filter
+// expression (an empty filter returns everything), following page tokens so
+// the result holds every match.
+func (n *NotebookClient) GetNotebooksWithFilter(filter string) (*ListNotebookRuntimeTemplatesResult, error) {
 
 	var templates ListNotebookRuntimeTemplatesResult
-	err = json.Unmarshal(body, &templates)
+	pageToken := ""
 
-	if err != nil {
-		return nil, err
+	for {
+		query := url.Values{}
+		if filter != "" {
+			query.Set("filter", filter)
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		endpoint := n.url
+		if len(query) > 0 {
+			endpoint = fmt.Sprintf("%s?%s", n.url, query.Encode())
+		}
+
+		body, err := n.curl(http.MethodGet, endpoint, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var page ListNotebookRuntimeTemplatesResult
+		err = json.Unmarshal(body, &page)
+
+		if err != nil {
+			return nil, err
+		}
+
+		templates.NotebookRuntimeTemplates = append(templates.NotebookRuntimeTemplates, page.NotebookRuntimeTemplates...)
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
 	}
 	return &templates, nil
 }
@@ -83,7 +126,7 @@ func (n *NotebookClient) GetNotebook(name string) (*NotebookRuntimeTemplate, err
 	return nil, fmt.Errorf("could not retrieve notebook: %s", name)
 }
 
-func (n *NotebookClient) CreateNotebook(template *NotebookRuntimeTemplate) (*NotebookRuntimeTemplate, error) {
+func (n *NotebookClient) CreateNotebook(ctx context.Context, template *NotebookRuntimeTemplate) (*NotebookRuntimeTemplate, error) {
 
 	payload, err := json.Marshal(template)
 
@@ -95,66 +138,28 @@ func (n *NotebookClient) CreateNotebook(template *NotebookRuntimeTemplate) (*Not
 		return nil, err
 	}
 
-	var respJSON map[string]interface{}
+	var created NotebookRuntimeTemplate
 
-	err = json.Unmarshal(body, &respJSON)
+	err = n.waitForOperation(ctx, body, &created)
 
 	if err != nil {
 		return nil, err
 	}
 
-	response, ok := respJSON["response"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("could not retrieve Response of newly created template")
-	}
-
-	name, ok := response["name"].(string)
-	if !ok {
+	if created.Name == nil {
 		return nil, fmt.Errorf("could not retrieve Name of newly created template")
 	}
 
-	return n.GetNotebook(name)
+	return n.GetNotebook(*created.Name)
 }
 
 func (n *NotebookClient) DeleteNotebookRuntimeTemplate(name string) error {
 
-	url := fmt.Sprintf("%s/%s", serviceEndpoint, name)
+	url := fmt.Sprintf("%s/%s", n.endpoint, name)
 	_, err := n.curl(http.MethodDelete, url, nil)
 	return err
 }
 
-func (nc *NotebookClient) curl(method string, url string, payload io.Reader) ([]byte, error) {
-
-	req, err := http.NewRequest(method, url, payload)
-
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", nc.token))
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode != 200 {
-		return nil, &ResponseError{Code: resp.StatusCode, Message: string(body)}
-	}
-
-	return body, nil
-}
-
 func (n *NotebookRuntimeTemplate) AsString() (string, error) {
 
 	jsonData, err := json.Marshal(n)
@@ -167,14 +172,24 @@ func (n *NotebookRuntimeTemplate) AsString() (string, error) {
 }
 
 func (nc *NotebookClient) UpdateNotebook(template *NotebookRuntimeTemplate) error {
+	return nc.PatchNotebook(template, []string{"encryptionSpec.kmsKeyName"})
+}
 
-	url := fmt.Sprintf("%s/%s?updateMask=encryptionSpec.kmsKeyName", serviceEndpoint, *template.Name)
+// PatchNotebook updates the fields of the template listed in mask
+func (nc *NotebookClient) PatchNotebook(template *NotebookRuntimeTemplate, mask []string) error {
+
+	endpoint := fmt.Sprintf("%s/%s?updateMask=%s", nc.endpoint, *template.Name, url.QueryEscape(strings.Join(mask, ",")))
 	payload, err := json.Marshal(template)
 
 	if err != nil {
 		return err
 	}
-	_, err = nc.curl(http.MethodPatch, url, bytes.NewBuffer(payload))
+	_, err = nc.curl(http.MethodPatch, endpoint, bytes.NewBuffer(payload))
 
 	return err
 }
+
+// SetDefault sets or clears the isDefault flag of the template
+func (nc *NotebookClient) SetDefault(name string, isDefault bool) error {
+	return nc.PatchNotebook(&NotebookRuntimeTemplate{Name: &name, IsDefault: &isDefault}, []string{"isDefault"})
+}
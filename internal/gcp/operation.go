@@ -0,0 +1,74 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	operationPollInterval = 5 * time.Second
+	operationTimeout      = 30 * time.Minute
+)
+
+// Operation is a long-running operation returned by mutating calls
+// https://cloud.google.com/vertex-ai/docs/reference/rest/Shared.Types/ListOperationsResponse#Operation
+type Operation struct {
+	Name     string          `json:"name"`
+	Done     bool            `json:"done,omitempty"`
+	Error    *OperationError `json:"error,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+type OperationError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("Operation failed (%d): %s", e.Code, e.Message)
+}
+
+// waitForOperation polls the operation in body until it is done, unmarshalling
+// its response into result (when not nil). It gives up when ctx is cancelled,
+// the operation itself carries on.
+func (t *transport) waitForOperation(ctx context.Context, body []byte, result interface{}) error {
+
+	var op Operation
+	if err := json.Unmarshal(body, &op); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(operationTimeout)
+
+	for !op.Done {
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for operation %s", op.Name)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for operation %s: %w", op.Name, ctx.Err())
+		case <-time.After(operationPollInterval):
+		}
+
+		body, err := t.curl(http.MethodGet, fmt.Sprintf("%s/%s", t.endpoint, op.Name), nil)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(body, &op); err != nil {
+			return err
+		}
+	}
+
+	if op.Error != nil {
+		return op.Error
+	}
+
+	if result != nil && len(op.Response) > 0 {
+		return json.Unmarshal(op.Response, result)
+	}
+	return nil
+}
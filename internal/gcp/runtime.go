@@ -0,0 +1,136 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Runtime states reported by the API
+const (
+	RuntimeStateRunning = "RUNNING"
+	RuntimeStateStopped = "STOPPED"
+)
+
+// AssignNotebookRuntime assigns a runtime created from the template to the
+// runtime user, waiting for it to be provisioned
+func (nc *NotebookClient) AssignNotebookRuntime(ctx context.Context, request *AssignNotebookRuntimeRequest) (*NotebookRuntime, error) {
+
+	payload, err := json.Marshal(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := nc.curl(http.MethodPost, fmt.Sprintf("%s/notebookRuntimes:assign", nc.parent), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	var runtime NotebookRuntime
+
+	err = nc.waitForOperation(ctx, body, &runtime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.Name == nil {
+		return nil, fmt.Errorf("could not retrieve Name of newly assigned runtime")
+	}
+
+	return nc.GetNotebookRuntime(*runtime.Name)
+}
+
+// GetNotebookRuntimes lists the runtimes matching the server-side filter
+// expression (an empty filter returns everything), following page tokens
+func (nc *NotebookClient) GetNotebookRuntimes(filter string) (*ListNotebookRuntimesResult, error) {
+
+	var runtimes ListNotebookRuntimesResult
+	pageToken := ""
+
+	for {
+		query := url.Values{}
+		if filter != "" {
+			query.Set("filter", filter)
+		}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		endpoint := fmt.Sprintf("%s/notebookRuntimes", nc.parent)
+		if len(query) > 0 {
+			endpoint = fmt.Sprintf("%s?%s", endpoint, query.Encode())
+		}
+
+		body, err := nc.curl(http.MethodGet, endpoint, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var page ListNotebookRuntimesResult
+		err = json.Unmarshal(body, &page)
+
+		if err != nil {
+			return nil, err
+		}
+
+		runtimes.NotebookRuntimes = append(runtimes.NotebookRuntimes, page.NotebookRuntimes...)
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return &runtimes, nil
+}
+
+func (nc *NotebookClient) GetNotebookRuntime(name string) (*NotebookRuntime, error) {
+
+	body, err := nc.curl(http.MethodGet, fmt.Sprintf("%s/%s", nc.endpoint, name), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var runtime NotebookRuntime
+	err = json.Unmarshal(body, &runtime)
+
+	if err != nil {
+		return nil, err
+	}
+	return &runtime, nil
+}
+
+func (nc *NotebookClient) StartNotebookRuntime(ctx context.Context, name string) error {
+	return nc.runtimeAction(ctx, name, "start")
+}
+
+func (nc *NotebookClient) StopNotebookRuntime(ctx context.Context, name string) error {
+	return nc.runtimeAction(ctx, name, "stop")
+}
+
+func (nc *NotebookClient) DeleteNotebookRuntime(ctx context.Context, name string) error {
+
+	body, err := nc.curl(http.MethodDelete, fmt.Sprintf("%s/%s", nc.endpoint, name), nil)
+
+	if err != nil {
+		return err
+	}
+	return nc.waitForOperation(ctx, body, nil)
+}
+
+// runtimeAction calls a custom method (e.g. :start) and waits for it to finish
+func (nc *NotebookClient) runtimeAction(ctx context.Context, name string, action string) error {
+
+	body, err := nc.curl(http.MethodPost, fmt.Sprintf("%s/%s:%s", nc.endpoint, name, action), bytes.NewBufferString("{}"))
+
+	if err != nil {
+		return err
+	}
+	return nc.waitForOperation(ctx, body, nil)
+}
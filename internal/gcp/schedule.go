@@ -0,0 +1,123 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Schedule states reported by the API
+const (
+	ScheduleStateActive    = "ACTIVE"
+	ScheduleStatePaused    = "PAUSED"
+	ScheduleStateCompleted = "COMPLETED"
+)
+
+// CreateSchedule creates a schedule running schedule.CreateNotebookExecutionJobRequest,
+// the request parent is filled in with the client location
+func (nc *NotebookClient) CreateSchedule(schedule *Schedule) (*Schedule, error) {
+
+	nc.setScheduleParent(schedule)
+
+	payload, err := json.Marshal(schedule)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := nc.curl(http.MethodPost, fmt.Sprintf("%s/schedules", nc.parent), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	var created Schedule
+	err = json.Unmarshal(body, &created)
+
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (nc *NotebookClient) GetSchedule(name string) (*Schedule, error) {
+
+	body, err := nc.curl(http.MethodGet, fmt.Sprintf("%s/%s", nc.endpoint, name), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule Schedule
+	err = json.Unmarshal(body, &schedule)
+
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// UpdateSchedule patches the fields in updateMask (API field names, e.g. "cron")
+func (nc *NotebookClient) UpdateSchedule(schedule *Schedule, updateMask []string) (*Schedule, error) {
+
+	nc.setScheduleParent(schedule)
+
+	payload, err := json.Marshal(schedule)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("updateMask", strings.Join(updateMask, ","))
+
+	body, err := nc.curl(http.MethodPatch, fmt.Sprintf("%s/%s?%s", nc.endpoint, *schedule.Name, query.Encode()), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	var updated Schedule
+	err = json.Unmarshal(body, &updated)
+
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (nc *NotebookClient) PauseSchedule(name string) error {
+
+	_, err := nc.curl(http.MethodPost, fmt.Sprintf("%s/%s:pause", nc.endpoint, name), bytes.NewBufferString("{}"))
+	return err
+}
+
+// ResumeSchedule resumes a paused schedule, without catching up on the runs
+// missed while paused
+func (nc *NotebookClient) ResumeSchedule(name string) error {
+
+	_, err := nc.curl(http.MethodPost, fmt.Sprintf("%s/%s:resume", nc.endpoint, name), bytes.NewBufferString(`{"catchUp":false}`))
+	return err
+}
+
+func (nc *NotebookClient) DeleteSchedule(ctx context.Context, name string) error {
+
+	body, err := nc.curl(http.MethodDelete, fmt.Sprintf("%s/%s", nc.endpoint, name), nil)
+
+	if err != nil {
+		return err
+	}
+	return nc.waitForOperation(ctx, body, nil)
+}
+
+// setScheduleParent points the execution job request at the client location
+func (nc *NotebookClient) setScheduleParent(schedule *Schedule) {
+
+	if schedule.CreateNotebookExecutionJobRequest == nil {
+		return
+	}
+	parent := strings.TrimPrefix(nc.parent, nc.endpoint+"/")
+	schedule.CreateNotebookExecutionJobRequest.Parent = &parent
+}
@@ -0,0 +1,31 @@
+package gcp
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// matches the full name of a runtime template
+var templateNameRegexp = regexp.MustCompile(`^projects/([^/]+)/locations/([^/]+)/notebookRuntimeTemplates/([^/]+)$`)
+
+// ParseTemplateName splits the full name of a runtime template
+func ParseTemplateName(name string) (project string, location string, id string, err error) {
+
+	matches := templateNameRegexp.FindStringSubmatch(name)
+
+	if matches == nil {
+		return "", "", "", fmt.Errorf("expected a name like projects/{project}/locations/{location}/notebookRuntimeTemplates/{id}, got: %s", name)
+	}
+	return matches[1], matches[2], matches[3], nil
+}
+
+// TemplateName builds the full name of a runtime template
+func TemplateName(project string, location string, id string) (string, error) {
+
+	name := fmt.Sprintf("projects/%s/locations/%s/notebookRuntimeTemplates/%s", project, location, id)
+
+	if !templateNameRegexp.MatchString(name) {
+		return "", fmt.Errorf("project, location and id must be non-empty and can't contain '/', got: %q, %q and %q", project, location, id)
+	}
+	return name, nil
+}
@@ -0,0 +1,64 @@
+package gcp
+
+import "testing"
+
+func TestParseTemplateName(t *testing.T) {
+
+	tests := []struct {
+		name                  string
+		project, location, id string
+		wantErr               bool
+	}{
+		{
+			name:     "projects/my-project/locations/us-central1/notebookRuntimeTemplates/123",
+			project:  "my-project",
+			location: "us-central1",
+			id:       "123",
+		},
+		{name: "projects/my-project/locations/us-central1/notebookRuntimeTemplates/", wantErr: true},
+		{name: "projects/my-project/locations/us-central1/notebookRuntimeTemplates/123/extra", wantErr: true},
+		{name: "projects/my-project/locations/us-central1/notebookRuntimes/123", wantErr: true},
+		{name: "123", wantErr: true},
+		{name: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			project, location, id, err := ParseTemplateName(tt.name)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q, %q and %q", project, location, id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if project != tt.project || location != tt.location || id != tt.id {
+				t.Fatalf("expected %q, %q and %q, got %q, %q and %q", tt.project, tt.location, tt.id, project, location, id)
+			}
+		})
+	}
+}
+
+func TestTemplateNameRoundTrip(t *testing.T) {
+
+	name, err := TemplateName("my-project", "us-central1", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, location, id, err := ParseTemplateName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project != "my-project" || location != "us-central1" || id != "123" {
+		t.Fatalf("expected the parts back, got %q, %q and %q", project, location, id)
+	}
+
+	if _, err := TemplateName("my-project", "us-central1", "a/b"); err == nil {
+		t.Fatal("expected an id containing '/' to be rejected")
+	}
+}
@@ -0,0 +1,135 @@
+package gcp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultMaxRetries is how often a request failing with a transient error is
+// retried when the configuration doesn't say otherwise
+const DefaultMaxRetries = 3
+
+const retryBackoff = 2 * time.Second
+
+// transport sends authenticated requests to one API endpoint, it is shared by
+// every client so they honour the same credentials, headers and retries
+type transport struct {
+	endpoint    string
+	tokenSource oauth2.TokenSource
+	userProject string
+	userAgent   string
+	maxRetries  int
+}
+
+// newTransport creates the transport for an endpoint, a "{{location}}"
+// placeholder in the endpoint is replaced with the location
+func (f *ClientFactory) newTransport(endpoint string, projectID string, location string) transport {
+
+	return transport{
+		endpoint:    strings.TrimSuffix(strings.ReplaceAll(endpoint, "{{location}}", location), "/"),
+		tokenSource: f.tokenSource,
+		userProject: f.config.userProject(projectID),
+		userAgent:   f.config.UserAgent,
+		maxRetries:  f.config.maxRetries(),
+	}
+}
+
+func (t *transport) curl(method string, url string, payload io.Reader) ([]byte, error) {
+
+	// keep the payload around so it can be sent again on a retry
+	var content []byte
+	if payload != nil {
+		var err error
+		if content, err = io.ReadAll(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+
+		var body io.Reader
+		if payload != nil {
+			body = bytes.NewReader(content)
+		}
+
+		req, err := http.NewRequest(method, url, body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		token, err := t.tokenSource.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+		req.Header.Set("Content-Type", "application/json")
+
+		if t.userProject != "" {
+			req.Header.Set("x-goog-user-project", t.userProject)
+		}
+
+		if t.userAgent != "" {
+			req.Header.Set("User-Agent", t.userAgent)
+		}
+
+		result, err := do(req)
+
+		if err == nil || !isRetryable(method, err) || attempt >= t.maxRetries {
+			return result, err
+		}
+		time.Sleep(retryBackoff * time.Duration(attempt+1))
+	}
+}
+
+// isRetryable reports whether err is a response worth sending the request again
+// for. A POST may have been carried out before a gateway error came back and
+// would create a duplicate, it is only retried when it was rate limited.
+func isRetryable(method string, err error) bool {
+
+	respErr, ok := err.(*ResponseError)
+	if !ok {
+		return false
+	}
+
+	switch respErr.Code {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return method != http.MethodPost
+	}
+	return false
+}
+
+// do sends the request, returning the body of a successful response or a
+// ResponseError
+func do(req *http.Request) ([]byte, error) {
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, &ResponseError{Code: resp.StatusCode, Message: string(body)}
+	}
+
+	return body, nil
+}
@@ -54,10 +54,236 @@ type NotebookRuntimeTemplate struct {
 	UpdateTime             *string                 `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
 	NotebookRuntimeType    *string                 `json:"notebookRuntimeType,omitempty" yaml:"notebookRuntimeType,omitempty"`
 	ShieldedVmConfig       *ShieldedVmConfig       `json:"shieldedVmConfig,omitempty" yaml:"shieldedVmConfig,omitempty"`
-	EncryptionSpec         *EncryptionSpec         `json:"encryptionSpec,omitempty"`
+	EncryptionSpec         *EncryptionSpec         `json:"encryptionSpec,omitempty" yaml:"encryptionSpec,omitempty"`
 }
 
 // this get's returned when we perform a GET
 type ListNotebookRuntimeTemplatesResult struct {
 	NotebookRuntimeTemplates []NotebookRuntimeTemplate `json:"notebookRuntimeTemplates"`
+	NextPageToken            string                    `json:"nextPageToken,omitempty"`
+}
+
+// https://cloud.google.com/vertex-ai/docs/reference/rest/v1beta1/projects.locations.notebookRuntimes
+
+type NotebookRuntimeTemplateRef struct {
+	NotebookRuntimeTemplate *string `json:"notebookRuntimeTemplate,omitempty" yaml:"notebookRuntimeTemplate,omitempty"`
+}
+
+type NotebookRuntime struct {
+	Name                       *string                     `json:"name,omitempty" yaml:"name,omitempty"`
+	RuntimeUser                *string                     `json:"runtimeUser,omitempty" yaml:"runtimeUser,omitempty"`
+	NotebookRuntimeTemplateRef *NotebookRuntimeTemplateRef `json:"notebookRuntimeTemplateRef,omitempty" yaml:"notebookRuntimeTemplateRef,omitempty"`
+	ProxyUri                   *string                     `json:"proxyUri,omitempty" yaml:"proxyUri,omitempty"`
+	CreateTime                 *string                     `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	UpdateTime                 *string                     `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+	HealthState                *string                     `json:"healthState,omitempty" yaml:"healthState,omitempty"`
+	DisplayName                *string                     `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Description                *string                     `json:"description,omitempty" yaml:"description,omitempty"`
+	ServiceAccount             *string                     `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
+	RuntimeState               *string                     `json:"runtimeState,omitempty" yaml:"runtimeState,omitempty"`
+	IsUpgradable               *bool                       `json:"isUpgradable,omitempty" yaml:"isUpgradable,omitempty"`
+	Labels                     *map[string]string          `json:"labels,omitempty" yaml:"labels,omitempty"`
+	ExpirationTime             *string                     `json:"expirationTime,omitempty" yaml:"expirationTime,omitempty"`
+	Version                    *string                     `json:"version,omitempty" yaml:"version,omitempty"`
+	NotebookRuntimeType        *string                     `json:"notebookRuntimeType,omitempty" yaml:"notebookRuntimeType,omitempty"`
+	MachineSpec                *MachineSpec                `json:"machineSpec,omitempty" yaml:"machineSpec,omitempty"`
+	DataPersistentDiskSpec     *DataPersistentDiskSpec     `json:"dataPersistentDiskSpec,omitempty" yaml:"dataPersistentDiskSpec,omitempty"`
+	NetworkSpec                *NetworkSpec                `json:"networkSpec,omitempty" yaml:"networkSpec,omitempty"`
+	IdleShutdownConfig         *IdleShutdownConfig         `json:"idleShutdownConfig,omitempty" yaml:"idleShutdownConfig,omitempty"`
+	EucConfig                  *EucConfig                  `json:"eucConfig,omitempty" yaml:"eucConfig,omitempty"`
+	ShieldedVmConfig           *ShieldedVmConfig           `json:"shieldedVmConfig,omitempty" yaml:"shieldedVmConfig,omitempty"`
+	EncryptionSpec             *EncryptionSpec             `json:"encryptionSpec,omitempty" yaml:"encryptionSpec,omitempty"`
+}
+
+// the body of a notebookRuntimes:assign call
+type AssignNotebookRuntimeRequest struct {
+	NotebookRuntimeTemplate string           `json:"notebookRuntimeTemplate"`
+	NotebookRuntime         *NotebookRuntime `json:"notebookRuntime"`
+	NotebookRuntimeId       string           `json:"notebookRuntimeId,omitempty"`
+}
+
+// this get's returned when we list the runtimes
+type ListNotebookRuntimesResult struct {
+	NotebookRuntimes []NotebookRuntime `json:"notebookRuntimes"`
+	NextPageToken    string            `json:"nextPageToken,omitempty"`
+}
+
+// https://cloud.google.com/vertex-ai/docs/reference/rest/v1beta1/projects.locations.notebookExecutionJobs
+
+type GcsNotebookSource struct {
+	Uri        *string `json:"uri,omitempty" yaml:"uri,omitempty"`
+	Generation *string `json:"generation,omitempty" yaml:"generation,omitempty"`
+}
+
+type DataformRepositorySource struct {
+	DataformRepositoryResourceName *string `json:"dataformRepositoryResourceName,omitempty" yaml:"dataformRepositoryResourceName,omitempty"`
+	CommitSha                      *string `json:"commitSha,omitempty" yaml:"commitSha,omitempty"`
+}
+
+type Status struct {
+	Code    int    `json:"code,omitempty" yaml:"code,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+type NotebookExecutionJob struct {
+	Name                                *string                   `json:"name,omitempty" yaml:"name,omitempty"`
+	DisplayName                         *string                   `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	DataformRepositorySource            *DataformRepositorySource `json:"dataformRepositorySource,omitempty" yaml:"dataformRepositorySource,omitempty"`
+	GcsNotebookSource                   *GcsNotebookSource        `json:"gcsNotebookSource,omitempty" yaml:"gcsNotebookSource,omitempty"`
+	NotebookRuntimeTemplateResourceName *string                   `json:"notebookRuntimeTemplateResourceName,omitempty" yaml:"notebookRuntimeTemplateResourceName,omitempty"`
+	GcsOutputUri                        *string                   `json:"gcsOutputUri,omitempty" yaml:"gcsOutputUri,omitempty"`
+	ExecutionUser                       *string                   `json:"executionUser,omitempty" yaml:"executionUser,omitempty"`
+	ServiceAccount                      *string                   `json:"serviceAccount,omitempty" yaml:"serviceAccount,omitempty"`
+	ExecutionTimeout                    *string                   `json:"executionTimeout,omitempty" yaml:"executionTimeout,omitempty"`
+	ScheduleResourceName                *string                   `json:"scheduleResourceName,omitempty" yaml:"scheduleResourceName,omitempty"`
+	JobState                            *string                   `json:"jobState,omitempty" yaml:"jobState,omitempty"`
+	Status                              *Status                   `json:"status,omitempty" yaml:"status,omitempty"`
+	CreateTime                          *string                   `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	UpdateTime                          *string                   `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+	Labels                              *map[string]string        `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+type CreateNotebookExecutionJobRequest struct {
+	Parent                 *string               `json:"parent,omitempty" yaml:"parent,omitempty"`
+	NotebookExecutionJob   *NotebookExecutionJob `json:"notebookExecutionJob,omitempty" yaml:"notebookExecutionJob,omitempty"`
+	NotebookExecutionJobId *string               `json:"notebookExecutionJobId,omitempty" yaml:"notebookExecutionJobId,omitempty"`
+}
+
+type Schedule struct {
+	Name                              *string                            `json:"name,omitempty" yaml:"name,omitempty"`
+	DisplayName                       *string                            `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Cron                              *string                            `json:"cron,omitempty" yaml:"cron,omitempty"`
+	StartTime                         *string                            `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	EndTime                           *string                            `json:"endTime,omitempty" yaml:"endTime,omitempty"`
+	MaxRunCount                       *int64                             `json:"maxRunCount,string,omitempty" yaml:"maxRunCount,omitempty"`
+	StartedRunCount                   *int64                             `json:"startedRunCount,string,omitempty" yaml:"startedRunCount,omitempty"`
+	MaxConcurrentRunCount             *int64                             `json:"maxConcurrentRunCount,string,omitempty" yaml:"maxConcurrentRunCount,omitempty"`
+	AllowQueueing                     *bool                              `json:"allowQueueing,omitempty" yaml:"allowQueueing,omitempty"`
+	State                             *string                            `json:"state,omitempty" yaml:"state,omitempty"`
+	NextRunTime                       *string                            `json:"nextRunTime,omitempty" yaml:"nextRunTime,omitempty"`
+	CreateTime                        *string                            `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	UpdateTime                        *string                            `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+	CreateNotebookExecutionJobRequest *CreateNotebookExecutionJobRequest `json:"createNotebookExecutionJobRequest,omitempty" yaml:"createNotebookExecutionJobRequest,omitempty"`
+}
+
+// WorkbenchInstance is a Vertex AI Workbench instance
+// https://cloud.google.com/vertex-ai/docs/workbench/reference/rest/v2/projects.locations.instances
+type WorkbenchInstance struct {
+	Name               *string            `json:"name,omitempty" yaml:"name,omitempty"`
+	GceSetup           *GceSetup          `json:"gceSetup,omitempty" yaml:"gceSetup,omitempty"`
+	ProxyUri           *string            `json:"proxyUri,omitempty" yaml:"proxyUri,omitempty"`
+	InstanceOwners     []string           `json:"instanceOwners,omitempty" yaml:"instanceOwners,omitempty"`
+	Creator            *string            `json:"creator,omitempty" yaml:"creator,omitempty"`
+	State              *string            `json:"state,omitempty" yaml:"state,omitempty"`
+	HealthState        *string            `json:"healthState,omitempty" yaml:"healthState,omitempty"`
+	DisableProxyAccess *bool              `json:"disableProxyAccess,omitempty" yaml:"disableProxyAccess,omitempty"`
+	CreateTime         *string            `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	UpdateTime         *string            `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+	Labels             *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+type GceSetup struct {
+	MachineType            *string                  `json:"machineType,omitempty" yaml:"machineType,omitempty"`
+	AcceleratorConfigs     []AcceleratorConfig      `json:"acceleratorConfigs,omitempty" yaml:"acceleratorConfigs,omitempty"`
+	ServiceAccounts        []ServiceAccount         `json:"serviceAccounts,omitempty" yaml:"serviceAccounts,omitempty"`
+	BootDisk               *WorkbenchDisk           `json:"bootDisk,omitempty" yaml:"bootDisk,omitempty"`
+	DataDisks              []WorkbenchDisk          `json:"dataDisks,omitempty" yaml:"dataDisks,omitempty"`
+	ShieldedInstanceConfig *ShieldedInstanceConfig  `json:"shieldedInstanceConfig,omitempty" yaml:"shieldedInstanceConfig,omitempty"`
+	NetworkInterfaces      []WorkbenchNetworkConfig `json:"networkInterfaces,omitempty" yaml:"networkInterfaces,omitempty"`
+	DisablePublicIp        *bool                    `json:"disablePublicIp,omitempty" yaml:"disablePublicIp,omitempty"`
+	Tags                   []string                 `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Metadata               *map[string]string       `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+type AcceleratorConfig struct {
+	Type      *string `json:"type,omitempty" yaml:"type,omitempty"`
+	CoreCount *int64  `json:"coreCount,string,omitempty" yaml:"coreCount,omitempty"`
+}
+
+type ServiceAccount struct {
+	Email  *string  `json:"email,omitempty" yaml:"email,omitempty"`
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// WorkbenchDisk is either the boot or a data disk, DiskEncryption is GMEK or CMEK
+type WorkbenchDisk struct {
+	DiskSizeGb     *int64  `json:"diskSizeGb,string,omitempty" yaml:"diskSizeGb,omitempty"`
+	DiskType       *string `json:"diskType,omitempty" yaml:"diskType,omitempty"`
+	DiskEncryption *string `json:"diskEncryption,omitempty" yaml:"diskEncryption,omitempty"`
+	KmsKey         *string `json:"kmsKey,omitempty" yaml:"kmsKey,omitempty"`
+}
+
+type ShieldedInstanceConfig struct {
+	EnableSecureBoot          *bool `json:"enableSecureBoot,omitempty" yaml:"enableSecureBoot,omitempty"`
+	EnableVtpm                *bool `json:"enableVtpm,omitempty" yaml:"enableVtpm,omitempty"`
+	EnableIntegrityMonitoring *bool `json:"enableIntegrityMonitoring,omitempty" yaml:"enableIntegrityMonitoring,omitempty"`
+}
+
+type WorkbenchNetworkConfig struct {
+	Network *string `json:"network,omitempty" yaml:"network,omitempty"`
+	Subnet  *string `json:"subnet,omitempty" yaml:"subnet,omitempty"`
+	NicType *string `json:"nicType,omitempty" yaml:"nicType,omitempty"`
+}
+
+type DataformRepository struct {
+	Name        *string            `json:"name,omitempty" yaml:"name,omitempty"`
+	DisplayName *string            `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	CreateTime  *string            `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	Labels      *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+type CommitAuthor struct {
+	Name         string `json:"name" yaml:"name"`
+	EmailAddress string `json:"emailAddress" yaml:"emailAddress"`
+}
+
+type CommitMetadata struct {
+	Author        CommitAuthor `json:"author" yaml:"author"`
+	CommitMessage string       `json:"commitMessage,omitempty" yaml:"commitMessage,omitempty"`
+}
+
+type WriteFile struct {
+	Contents string `json:"contents" yaml:"contents"`
+}
+
+type FileOperation struct {
+	WriteFile *WriteFile `json:"writeFile,omitempty" yaml:"writeFile,omitempty"`
+}
+
+type CommitRepositoryChangesRequest struct {
+	CommitMetadata CommitMetadata           `json:"commitMetadata" yaml:"commitMetadata"`
+	FileOperations map[string]FileOperation `json:"fileOperations" yaml:"fileOperations"`
+}
+
+type CommitRepositoryChangesResponse struct {
+	CommitSha string `json:"commitSha,omitempty" yaml:"commitSha,omitempty"`
+}
+
+type ReadRepositoryFileResponse struct {
+	Contents string `json:"contents,omitempty" yaml:"contents,omitempty"`
+}
+
+// MachineType is a Compute Engine machine type in a zone
+type MachineType struct {
+	Name         *string              `json:"name,omitempty" yaml:"name,omitempty"`
+	Description  *string              `json:"description,omitempty" yaml:"description,omitempty"`
+	GuestCpus    *int64               `json:"guestCpus,omitempty" yaml:"guestCpus,omitempty"`
+	MemoryMb     *int64               `json:"memoryMb,omitempty" yaml:"memoryMb,omitempty"`
+	IsSharedCpu  *bool                `json:"isSharedCpu,omitempty" yaml:"isSharedCpu,omitempty"`
+	Accelerators []MachineAccelerator `json:"accelerators,omitempty" yaml:"accelerators,omitempty"`
+	Zone         *string              `json:"zone,omitempty" yaml:"zone,omitempty"`
+}
+
+// MachineAccelerator is a GPU bundled with a machine type (e.g. a2 and g2)
+type MachineAccelerator struct {
+	GuestAcceleratorType  *string `json:"guestAcceleratorType,omitempty" yaml:"guestAcceleratorType,omitempty"`
+	GuestAcceleratorCount *int64  `json:"guestAcceleratorCount,omitempty" yaml:"guestAcceleratorCount,omitempty"`
+}
+
+// AcceleratorType is a Compute Engine accelerator type in a zone
+type AcceleratorType struct {
+	Name                    *string `json:"name,omitempty" yaml:"name,omitempty"`
+	Description             *string `json:"description,omitempty" yaml:"description,omitempty"`
+	MaximumCardsPerInstance *int64  `json:"maximumCardsPerInstance,omitempty" yaml:"maximumCardsPerInstance,omitempty"`
+	Zone                    *string `json:"zone,omitempty" yaml:"zone,omitempty"`
 }
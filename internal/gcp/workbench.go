@@ -0,0 +1,138 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Workbench instances are served by the Notebooks API
+const workbenchEndpoint = "https://notebooks.googleapis.com/v2"
+
+// Instance states reported by the API
+const (
+	InstanceStateActive  = "ACTIVE"
+	InstanceStateStopped = "STOPPED"
+)
+
+// WorkbenchClient manages Vertex AI Workbench instances in a single project and zone
+type WorkbenchClient struct {
+	transport
+	parent string
+}
+
+func newWorkbenchClient(projectID string, zone string, transport transport) *WorkbenchClient {
+
+	return &WorkbenchClient{
+		transport: transport,
+		parent:    fmt.Sprintf("%s/projects/%s/locations/%s", transport.endpoint, projectID, zone),
+	}
+}
+
+// CreateInstance creates the instance, waiting for it to be provisioned
+func (wc *WorkbenchClient) CreateInstance(ctx context.Context, instanceID string, instance *WorkbenchInstance) (*WorkbenchInstance, error) {
+
+	payload, err := json.Marshal(instance)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("instanceId", instanceID)
+
+	body, err := wc.curl(http.MethodPost, fmt.Sprintf("%s/instances?%s", wc.parent, query.Encode()), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	var created WorkbenchInstance
+
+	err = wc.waitForOperation(ctx, body, &created)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if created.Name == nil {
+		return nil, fmt.Errorf("could not retrieve Name of newly created instance")
+	}
+
+	return wc.GetInstance(*created.Name)
+}
+
+func (wc *WorkbenchClient) GetInstance(name string) (*WorkbenchInstance, error) {
+
+	body, err := wc.curl(http.MethodGet, fmt.Sprintf("%s/%s", wc.endpoint, name), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var instance WorkbenchInstance
+	err = json.Unmarshal(body, &instance)
+
+	if err != nil {
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// UpdateInstance patches the fields in updateMask (API field names, e.g.
+// "gce_setup.machine_type"), some fields can only be changed while stopped
+func (wc *WorkbenchClient) UpdateInstance(ctx context.Context, instance *WorkbenchInstance, updateMask []string) (*WorkbenchInstance, error) {
+
+	payload, err := json.Marshal(instance)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("updateMask", strings.Join(updateMask, ","))
+
+	body, err := wc.curl(http.MethodPatch, fmt.Sprintf("%s/%s?%s", wc.endpoint, *instance.Name, query.Encode()), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	err = wc.waitForOperation(ctx, body, nil)
+
+	if err != nil {
+		return nil, err
+	}
+	return wc.GetInstance(*instance.Name)
+}
+
+func (wc *WorkbenchClient) StartInstance(ctx context.Context, name string) error {
+	return wc.instanceAction(ctx, name, "start")
+}
+
+func (wc *WorkbenchClient) StopInstance(ctx context.Context, name string) error {
+	return wc.instanceAction(ctx, name, "stop")
+}
+
+func (wc *WorkbenchClient) DeleteInstance(ctx context.Context, name string) error {
+
+	body, err := wc.curl(http.MethodDelete, fmt.Sprintf("%s/%s", wc.endpoint, name), nil)
+
+	if err != nil {
+		return err
+	}
+	return wc.waitForOperation(ctx, body, nil)
+}
+
+// instanceAction calls a custom method (e.g. :start) and waits for it to finish
+func (wc *WorkbenchClient) instanceAction(ctx context.Context, name string, action string) error {
+
+	body, err := wc.curl(http.MethodPost, fmt.Sprintf("%s/%s:%s", wc.endpoint, name, action), bytes.NewBufferString("{}"))
+
+	if err != nil {
+		return err
+	}
+	return wc.waitForOperation(ctx, body, nil)
+}
@@ -0,0 +1,170 @@
+// Package pricing estimates what a runtime costs to run from a price
+// catalogue. A catalogue is embedded so estimates work offline, a price file
+// can override any of its prices.
+package pricing
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// hours in an average month, disks are priced per GB month
+const hoursPerMonth = 730
+
+//go:embed catalogue.json
+var embeddedCatalogue []byte
+
+// Catalogue holds on-demand list prices per region
+type Catalogue struct {
+	Currency string `json:"currency"`
+	Updated  string `json:"updated,omitempty"`
+
+	// BundledAcceleratorFamilies are the machine families whose price
+	// includes their GPUs, e.g. a2
+	BundledAcceleratorFamilies []string `json:"bundled_accelerator_families,omitempty"`
+
+	Regions map[string]RegionPrices `json:"regions"`
+}
+
+type RegionPrices struct {
+	// MachineTypes is the hourly price of a machine type, e.g. "n1-standard-4"
+	MachineTypes map[string]float64 `json:"machine_types,omitempty"`
+
+	// Accelerators is the hourly price of one accelerator, e.g. "NVIDIA_TESLA_T4"
+	Accelerators map[string]float64 `json:"accelerators,omitempty"`
+
+	// DisksGbMonth is the monthly price of a GB of disk, e.g. "pd-standard"
+	DisksGbMonth map[string]float64 `json:"disks_gb_month,omitempty"`
+}
+
+// Spec is what's priced, zero values are left out of the estimate
+type Spec struct {
+	Region           string
+	MachineType      string
+	AcceleratorType  string
+	AcceleratorCount int64
+	DiskType         string
+	DiskSizeGb       int64
+}
+
+// Default returns the embedded catalogue
+func Default() (*Catalogue, error) {
+
+	var catalogue Catalogue
+
+	if err := json.Unmarshal(embeddedCatalogue, &catalogue); err != nil {
+		return nil, fmt.Errorf("could not parse the embedded price catalogue: %w", err)
+	}
+	return &catalogue, nil
+}
+
+// Load returns the embedded catalogue with the prices in the file (same
+// format) laid over it, the file only needs the prices it changes
+func Load(path string) (*Catalogue, error) {
+
+	catalogue, err := Default()
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read price file: %w", err)
+	}
+
+	var overrides Catalogue
+	if err := json.Unmarshal(contents, &overrides); err != nil {
+		return nil, fmt.Errorf("could not parse price file %s: %w", path, err)
+	}
+
+	catalogue.merge(&overrides)
+	return catalogue, nil
+}
+
+func (c *Catalogue) merge(overrides *Catalogue) {
+
+	if overrides.Currency != "" {
+		c.Currency = overrides.Currency
+	}
+	if overrides.Updated != "" {
+		c.Updated = overrides.Updated
+	}
+	if overrides.BundledAcceleratorFamilies != nil {
+		c.BundledAcceleratorFamilies = overrides.BundledAcceleratorFamilies
+	}
+
+	for region, prices := range overrides.Regions {
+
+		current := c.Regions[region]
+		current.MachineTypes = mergePrices(current.MachineTypes, prices.MachineTypes)
+		current.Accelerators = mergePrices(current.Accelerators, prices.Accelerators)
+		current.DisksGbMonth = mergePrices(current.DisksGbMonth, prices.DisksGbMonth)
+		c.Regions[region] = current
+	}
+}
+
+func mergePrices(prices map[string]float64, overrides map[string]float64) map[string]float64 {
+
+	merged := make(map[string]float64, len(prices)+len(overrides))
+	for key, price := range prices {
+		merged[key] = price
+	}
+	for key, price := range overrides {
+		merged[key] = price
+	}
+	return merged
+}
+
+// HourlyCost estimates the hourly cost of running spec, failing when the
+// catalogue is missing one of the prices
+func (c *Catalogue) HourlyCost(spec Spec) (float64, error) {
+
+	prices, ok := c.Regions[spec.Region]
+	if !ok {
+		return 0, fmt.Errorf("no prices for region %s", spec.Region)
+	}
+
+	cost := 0.0
+
+	if spec.MachineType != "" {
+		price, ok := prices.MachineTypes[spec.MachineType]
+		if !ok {
+			return 0, fmt.Errorf("no price for machine type %s in %s", spec.MachineType, spec.Region)
+		}
+		cost += price
+	}
+
+	family, _, _ := strings.Cut(spec.MachineType, "-")
+
+	if spec.AcceleratorType != "" && !slices.Contains(c.BundledAcceleratorFamilies, family) {
+
+		price, ok := prices.Accelerators[spec.AcceleratorType]
+		if !ok {
+			return 0, fmt.Errorf("no price for accelerator type %s in %s", spec.AcceleratorType, spec.Region)
+		}
+
+		count := spec.AcceleratorCount
+		if count == 0 {
+			count = 1
+		}
+		cost += price * float64(count)
+	}
+
+	if spec.DiskType != "" && spec.DiskSizeGb > 0 {
+
+		// the API takes both PD_STANDARD and pd-standard
+		diskType := strings.ToLower(strings.ReplaceAll(spec.DiskType, "_", "-"))
+
+		price, ok := prices.DisksGbMonth[diskType]
+		if !ok {
+			return 0, fmt.Errorf("no price for disk type %s in %s", spec.DiskType, spec.Region)
+		}
+		cost += price * float64(spec.DiskSizeGb) / hoursPerMonth
+	}
+
+	return cost, nil
+}
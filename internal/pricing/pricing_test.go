@@ -0,0 +1,106 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHourlyCost(t *testing.T) {
+
+	catalogue := &Catalogue{
+		Currency:                   "USD",
+		BundledAcceleratorFamilies: []string{"a2"},
+		Regions: map[string]RegionPrices{
+			"us-central1": {
+				MachineTypes: map[string]float64{"n1-standard-4": 0.2, "a2-highgpu-1g": 3.5},
+				Accelerators: map[string]float64{"NVIDIA_TESLA_T4": 0.35, "NVIDIA_TESLA_A100": 2.9},
+				DisksGbMonth: map[string]float64{"pd-standard": 0.073},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spec    Spec
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "machine only",
+			spec: Spec{Region: "us-central1", MachineType: "n1-standard-4"},
+			want: 0.2,
+		},
+		{
+			name: "accelerator count defaults to one",
+			spec: Spec{Region: "us-central1", MachineType: "n1-standard-4", AcceleratorType: "NVIDIA_TESLA_T4"},
+			want: 0.55,
+		},
+		{
+			name: "accelerators are priced each",
+			spec: Spec{Region: "us-central1", MachineType: "n1-standard-4", AcceleratorType: "NVIDIA_TESLA_T4", AcceleratorCount: 2},
+			want: 0.9,
+		},
+		{
+			name: "bundled accelerators are in the machine price",
+			spec: Spec{Region: "us-central1", MachineType: "a2-highgpu-1g", AcceleratorType: "NVIDIA_TESLA_A100", AcceleratorCount: 1},
+			want: 3.5,
+		},
+		{
+			name: "disk is priced per GB month",
+			spec: Spec{Region: "us-central1", MachineType: "n1-standard-4", DiskType: "PD_STANDARD", DiskSizeGb: 100},
+			want: 0.2 + 0.073*100/hoursPerMonth,
+		},
+		{
+			name:    "unknown region",
+			spec:    Spec{Region: "europe-west4", MachineType: "n1-standard-4"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown machine type",
+			spec:    Spec{Region: "us-central1", MachineType: "e2-standard-4"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown accelerator type",
+			spec:    Spec{Region: "us-central1", MachineType: "n1-standard-4", AcceleratorType: "NVIDIA_L4"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown disk type",
+			spec:    Spec{Region: "us-central1", MachineType: "n1-standard-4", DiskType: "pd-ssd", DiskSizeGb: 100},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, err := catalogue.HourlyCost(tt.spec)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %f", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("expected %f, got %f", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+
+	catalogue, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := catalogue.HourlyCost(Spec{Region: "us-central1", MachineType: "n1-standard-4", AcceleratorType: "NVIDIA_TESLA_T4", DiskType: "pd-standard", DiskSizeGb: 100}); err != nil {
+		t.Fatalf("the embedded catalogue can't price a common template: %v", err)
+	}
+}
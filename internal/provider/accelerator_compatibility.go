@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// acceleratorCompatibility lists the machine families an accelerator type
+// attaches to and how many of it a machine can take
+type acceleratorCompatibility struct {
+	machineFamilies []string
+	counts          []int64
+}
+
+// builtinAcceleratorCompatibility is used unless the provider overrides an
+// accelerator type, https://cloud.google.com/compute/docs/gpus. Its keys are
+// the accelerator types Colab Enterprise runtimes can use.
+var builtinAcceleratorCompatibility = map[string]acceleratorCompatibility{
+	"NVIDIA_TESLA_P4":   {machineFamilies: []string{"n1"}, counts: []int64{1, 2, 4}},
+	"NVIDIA_TESLA_P100": {machineFamilies: []string{"n1"}, counts: []int64{1, 2, 4}},
+	"NVIDIA_TESLA_T4":   {machineFamilies: []string{"n1"}, counts: []int64{1, 2, 4}},
+	"NVIDIA_TESLA_V100": {machineFamilies: []string{"n1"}, counts: []int64{1, 2, 4, 8}},
+	"NVIDIA_TESLA_A100": {machineFamilies: []string{"a2"}, counts: []int64{1, 2, 4, 8, 16}},
+	"NVIDIA_A100_80GB":  {machineFamilies: []string{"a2"}, counts: []int64{1, 2, 4, 8}},
+	"NVIDIA_L4":         {machineFamilies: []string{"g2"}, counts: []int64{1, 2, 4, 8}},
+}
+
+// machine types with GPUs attached end in their GPU count, e.g. a2-highgpu-2g
+var bundledAcceleratorCount = regexp.MustCompile(`-(\d+)g$`)
+
+// acceleratorCompatibilityTable merges the provider overrides over the
+// built-in table, an override replaces the whole entry of its accelerator type
+func acceleratorCompatibilityTable(ctx context.Context, overrides map[string]acceleratorCompatibilityModel) (map[string]acceleratorCompatibility, diag.Diagnostics) {
+
+	var diags diag.Diagnostics
+
+	table := make(map[string]acceleratorCompatibility, len(builtinAcceleratorCompatibility)+len(overrides))
+	for acceleratorType, compatibility := range builtinAcceleratorCompatibility {
+		table[acceleratorType] = compatibility
+	}
+
+	for acceleratorType, override := range overrides {
+
+		var compatibility acceleratorCompatibility
+
+		diags.Append(override.MachineFamilies.ElementsAs(ctx, &compatibility.machineFamilies, false)...)
+		diags.Append(override.Counts.ElementsAs(ctx, &compatibility.counts, false)...)
+
+		table[acceleratorType] = compatibility
+	}
+	return table, diags
+}
+
+// validateMachineSpec checks the accelerator of the machine spec against the
+// table. Accelerator types missing from the table are only reported when
+// strict, i.e. when the provider overrides are known.
+func validateMachineSpec(table map[string]acceleratorCompatibility, strict bool, spec notebookMachineSpecModel) diag.Diagnostics {
+
+	var diags diag.Diagnostics
+
+	if spec.MachineType.IsNull() || spec.MachineType.IsUnknown() || spec.AcceleratorType.IsNull() || spec.AcceleratorType.IsUnknown() || spec.AcceleratorCount.IsUnknown() {
+		return diags
+	}
+
+	machineType := spec.MachineType.ValueString()
+	acceleratorType := spec.AcceleratorType.ValueString()
+	family := machineFamily(machineType)
+
+	compatibility, ok := table[acceleratorType]
+
+	if !ok {
+		if strict {
+			diags.AddAttributeError(
+				tfpath.Root("machine_spec").AtName("accelerator_type"),
+				"Unknown accelerator type",
+				fmt.Sprintf("%s is not one of %s, add it to the provider accelerator_compatibility to use it", acceleratorType, strings.Join(sortedKeys(table), ", ")),
+			)
+		}
+		return diags
+	}
+
+	if !slices.Contains(compatibility.machineFamilies, family) {
+		diags.AddAttributeError(
+			tfpath.Root("machine_spec").AtName("accelerator_type"),
+			"Accelerator type not supported by machine type",
+			fmt.Sprintf("%s can't be attached to %s, it only attaches to %s machine types", acceleratorType, machineType, strings.Join(compatibility.machineFamilies, " or ")),
+		)
+		return diags
+	}
+
+	// Vertex AI attaches a single accelerator unless told otherwise
+	count := int64(1)
+	if !spec.AcceleratorCount.IsNull() {
+		count = spec.AcceleratorCount.ValueInt64()
+	}
+
+	if !slices.Contains(compatibility.counts, count) {
+		counts := make([]string, len(compatibility.counts))
+		for i, allowed := range compatibility.counts {
+			counts[i] = strconv.FormatInt(allowed, 10)
+		}
+
+		diags.AddAttributeError(
+			tfpath.Root("machine_spec").AtName("accelerator_count"),
+			"Unsupported accelerator count",
+			fmt.Sprintf("%s can't be attached %d times, expected one of %s", acceleratorType, count, strings.Join(counts, ", ")),
+		)
+		return diags
+	}
+
+	if match := bundledAcceleratorCount.FindStringSubmatch(path.Base(machineType)); match != nil {
+		if bundled, _ := strconv.ParseInt(match[1], 10, 64); bundled != count {
+			diags.AddAttributeError(
+				tfpath.Root("machine_spec").AtName("accelerator_count"),
+				"Accelerator count doesn't match machine type",
+				fmt.Sprintf("%s comes with %d %s, got accelerator_count %d", machineType, bundled, acceleratorType, count),
+			)
+		}
+	}
+
+	return diags
+}
+
+func sortedKeys(table map[string]acceleratorCompatibility) []string {
+
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func machineSpec(machineType string, acceleratorType string, count types.Int64) notebookMachineSpecModel {
+	return notebookMachineSpecModel{
+		MachineType:      types.StringValue(machineType),
+		AcceleratorType:  types.StringValue(acceleratorType),
+		AcceleratorCount: count,
+	}
+}
+
+func TestValidateMachineSpec(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		spec   notebookMachineSpecModel
+		strict bool
+		want   string
+	}{
+		{
+			name: "t4 on n1",
+			spec: machineSpec("n1-standard-4", "NVIDIA_TESLA_T4", types.Int64Value(1)),
+		},
+		{
+			name: "count defaults to one",
+			spec: machineSpec("n1-standard-4", "NVIDIA_TESLA_T4", types.Int64Null()),
+		},
+		{
+			name: "t4 on e2",
+			spec: machineSpec("e2-standard-4", "NVIDIA_TESLA_T4", types.Int64Value(1)),
+			want: "Accelerator type not supported by machine type",
+		},
+		{
+			name: "three l4",
+			spec: machineSpec("g2-standard-48", "NVIDIA_L4", types.Int64Value(3)),
+			want: "Unsupported accelerator count",
+		},
+		{
+			name: "a2-highgpu-2g with one a100",
+			spec: machineSpec("a2-highgpu-2g", "NVIDIA_TESLA_A100", types.Int64Value(1)),
+			want: "Accelerator count doesn't match machine type",
+		},
+		{
+			name: "a2-highgpu-2g with two a100",
+			spec: machineSpec("a2-highgpu-2g", "NVIDIA_TESLA_A100", types.Int64Value(2)),
+		},
+		{
+			name:   "unknown accelerator type when strict",
+			spec:   machineSpec("n1-standard-4", "NVIDIA_B200", types.Int64Value(1)),
+			strict: true,
+			want:   "Unknown accelerator type",
+		},
+		{
+			name: "unknown accelerator type when not strict",
+			spec: machineSpec("n1-standard-4", "NVIDIA_B200", types.Int64Value(1)),
+		},
+		{
+			name: "unknown count",
+			spec: machineSpec("g2-standard-48", "NVIDIA_L4", types.Int64Unknown()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			diags := validateMachineSpec(builtinAcceleratorCompatibility, tt.strict, tt.spec)
+
+			if tt.want == "" {
+				if diags.HasError() {
+					t.Fatalf("expected no errors, got %v", diags)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Summary() != tt.want {
+				t.Fatalf("expected a single %q error, got %v", tt.want, diags)
+			}
+		})
+	}
+}
+
+func TestAcceleratorCompatibilityTable(t *testing.T) {
+
+	ctx := context.Background()
+
+	overrides := map[string]acceleratorCompatibilityModel{
+		"NVIDIA_TESLA_T4": {
+			MachineFamilies: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("n1")}),
+			Counts:          types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(1), types.Int64Value(8)}),
+		},
+		"NVIDIA_B200": {
+			MachineFamilies: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a4")}),
+			Counts:          types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(8)}),
+		},
+	}
+
+	table, diags := acceleratorCompatibilityTable(ctx, overrides)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	tests := []struct {
+		name  string
+		spec  notebookMachineSpecModel
+		valid bool
+	}{
+		{"override loosens a built-in count", machineSpec("n1-standard-32", "NVIDIA_TESLA_T4", types.Int64Value(8)), true},
+		{"override replaces the built-in counts", machineSpec("n1-standard-8", "NVIDIA_TESLA_T4", types.Int64Value(2)), false},
+		{"override adds an accelerator type", machineSpec("a4-highgpu-8g", "NVIDIA_B200", types.Int64Value(8)), true},
+		{"built-in entries are kept", machineSpec("g2-standard-24", "NVIDIA_L4", types.Int64Value(2)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			diags := validateMachineSpec(table, true, tt.spec)
+
+			if diags.HasError() == tt.valid {
+				t.Fatalf("expected valid to be %t, got %v", tt.valid, diags)
+			}
+		})
+	}
+
+	if counts := builtinAcceleratorCompatibility["NVIDIA_TESLA_T4"].counts; len(counts) != 3 {
+		t.Fatalf("the built-in table was modified, NVIDIA_TESLA_T4 counts are %v", counts)
+	}
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/mpstella/terraform-provider-daw/internal/pricing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Vertex AI shuts runtimes down after 180 minutes of idling unless told otherwise
+const defaultIdleTimeoutHours = 3.0
+
+// estimateCosts prices a template, returning its hourly cost and the cost of a
+// runtime left idle for a day: until it shuts down, or all day when idle
+// shutdown is disabled. Costs are unknown while the spec is and null when the
+// catalogue has no price for it.
+func (d *providerData) estimateCosts(ctx context.Context, location string, machine *notebookMachineSpecModel, disk *notebookDataPersistentDiskSpecModel, idle *notebookIdleShutdownConfigModel) (types.Float64, types.Float64) {
+
+	if d == nil || d.prices == nil || machine == nil {
+		return types.Float64Null(), types.Float64Null()
+	}
+
+	spec := pricing.Spec{Region: location}
+
+	values := []interface{ IsUnknown() bool }{machine.MachineType, machine.AcceleratorType, machine.AcceleratorCount}
+	if disk != nil {
+		values = append(values, disk.DiskType, disk.DiskSizeGb)
+	}
+	if idle != nil {
+		values = append(values, idle.IdleTimeout, idle.IdleShutdownDisabled)
+	}
+	for _, value := range values {
+		if value.IsUnknown() {
+			return types.Float64Unknown(), types.Float64Unknown()
+		}
+	}
+
+	spec.MachineType = machine.MachineType.ValueString()
+	spec.AcceleratorType = machine.AcceleratorType.ValueString()
+	spec.AcceleratorCount = machine.AcceleratorCount.ValueInt64()
+
+	if disk != nil {
+		spec.DiskType = disk.DiskType.ValueString()
+		spec.DiskSizeGb, _ = strconv.ParseInt(disk.DiskSizeGb.ValueString(), 10, 64)
+	}
+
+	hourly, err := d.prices.HourlyCost(spec)
+
+	if err != nil {
+		tflog.Debug(ctx, "Could not estimate the cost of the template", map[string]interface{}{"error": err.Error()})
+		return types.Float64Null(), types.Float64Null()
+	}
+
+	idleHours := defaultIdleTimeoutHours
+
+	if idle != nil {
+		if idle.IdleShutdownDisabled.ValueBool() {
+			idleHours = 24
+		} else if !idle.IdleTimeout.IsNull() {
+			if timeout, err := parseDurationSeconds(idle.IdleTimeout.ValueString()); err == nil {
+				idleHours = math.Min(timeout.Hours(), 24)
+			}
+		}
+	}
+
+	return types.Float64Value(roundCost(hourly)), types.Float64Value(roundCost(hourly * idleHours))
+}
+
+// roundCost keeps costs to a hundredth of a cent, enough to compare plans
+func roundCost(cost float64) float64 {
+	return math.Round(cost*10000) / 10000
+}
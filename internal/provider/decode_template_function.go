@@ -0,0 +1,353 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gopkg.in/yaml.v3"
+)
+
+var _ function.Function = &decodeTemplateFunction{}
+
+// decodeTemplateFunction turns a YAML (or JSON) template spec, in the shape
+// of the API's NotebookRuntimeTemplate, into the arguments of daw_notebook
+type decodeTemplateFunction struct{}
+
+func NewDecodeTemplateFunction() function.Function {
+	return &decodeTemplateFunction{}
+}
+
+// decodedTemplateModel holds the daw_notebook arguments, specs missing from
+// the document are null
+type decodedTemplateModel struct {
+	DisplayName            types.String                         `tfsdk:"display_name"`
+	Description            types.String                         `tfsdk:"description"`
+	IsDefault              types.Bool                           `tfsdk:"is_default"`
+	KmsKeyName             types.String                         `tfsdk:"kms_key_name"`
+	MachineSpec            *notebookMachineSpecModel            `tfsdk:"machine_spec"`
+	DataPersistentDiskSpec *notebookDataPersistentDiskSpecModel `tfsdk:"data_persistent_disk_spec"`
+	NetworkSpec            *notebookNetworkSpecModel            `tfsdk:"network_spec"`
+	IdleShutdownConfig     *notebookIdleShutdownConfigModel     `tfsdk:"idle_shutdown_config"`
+	Labels                 types.Map                            `tfsdk:"labels"`
+}
+
+var decodedTemplateAttributeTypes = map[string]attr.Type{
+	"display_name": types.StringType,
+	"description":  types.StringType,
+	"is_default":   types.BoolType,
+	"kms_key_name": types.StringType,
+	"machine_spec": types.ObjectType{AttrTypes: map[string]attr.Type{
+		"machine_type":      types.StringType,
+		"accelerator_type":  types.StringType,
+		"accelerator_count": types.Int64Type,
+	}},
+	"data_persistent_disk_spec": types.ObjectType{AttrTypes: map[string]attr.Type{
+		"disk_type":    types.StringType,
+		"disk_size_gb": types.StringType,
+	}},
+	"network_spec": types.ObjectType{AttrTypes: map[string]attr.Type{
+		"enable_internet_access": types.BoolType,
+		"network":                types.StringType,
+		"subnetwork":             types.StringType,
+	}},
+	"idle_shutdown_config": types.ObjectType{AttrTypes: map[string]attr.Type{
+		"idle_timeout":           types.StringType,
+		"idle_shutdown_disabled": types.BoolType,
+	}},
+	"labels": types.MapType{ElemType: types.StringType},
+}
+
+// fields of the API resource daw_notebook doesn't take as arguments
+var unsupportedTemplateFields = []string{"name", "etag", "createTime", "updateTime", "notebookRuntimeType", "serviceAccount", "eucConfig", "shieldedVmConfig"}
+
+func (f *decodeTemplateFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "decode_template"
+}
+
+func (f *decodeTemplateFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Decode a YAML or JSON runtime template spec",
+		MarkdownDescription: "Decodes a runtime template written as YAML or JSON, with the field names of the API " +
+			"(`displayName`, `machineSpec.machineType`, ...), into an object holding the arguments of `daw_notebook`. " +
+			"The document is checked against the rules of the resource schema, errors carry the line they were found on. " +
+			"Values `daw_notebook` only warns about are logged as warnings and reported again when planning.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "document",
+				MarkdownDescription: "The YAML or JSON document, e.g. `file(\"templates/gpu.yaml\")`",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: decodedTemplateAttributeTypes,
+		},
+	}
+}
+
+func (f *decodeTemplateFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+
+	var document string
+
+	resp.Error = req.Arguments.Get(ctx, &document)
+	if resp.Error != nil {
+		return
+	}
+
+	template, errs, warnings := decodeTemplate(document)
+	if len(errs) > 0 {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid template document:\n"+strings.Join(errs, "\n"))
+		return
+	}
+
+	// functions can't return warnings, daw_notebook reports them again when planning
+	for _, warning := range warnings {
+		tflog.Warn(ctx, "decode_template: "+warning)
+	}
+
+	result := decodedTemplateModel{
+		DisplayName: types.StringPointerValue(template.DisplayName),
+		Description: types.StringPointerValue(template.Description),
+		IsDefault:   types.BoolPointerValue(template.IsDefault),
+		KmsKeyName:  types.StringNull(),
+		Labels:      types.MapNull(types.StringType),
+	}
+
+	if template.EncryptionSpec != nil {
+		result.KmsKeyName = types.StringPointerValue(template.EncryptionSpec.KmsKeyName)
+	}
+
+	result.MachineSpec, result.DataPersistentDiskSpec, result.NetworkSpec, result.IdleShutdownConfig = decodedSpecs(template)
+
+	if template.Labels != nil {
+		labels, diags := types.MapValueFrom(ctx, types.StringType, *template.Labels)
+		if diags.HasError() {
+			resp.Error = function.FuncErrorFromDiags(ctx, diags)
+			return
+		}
+		result.Labels = labels
+	}
+
+	resp.Error = resp.Result.Set(ctx, &result)
+}
+
+// yaml reports unknown fields against the Go type, e.g. "in type gcp.MachineSpec"
+var yamlTypeSuffix = regexp.MustCompile(` in type [\w.*]+`)
+
+// decodeTemplate parses and validates the document, each problem is
+// reported as "line N: ...". Warnings are the rules daw_notebook only warns
+// about, see checkSpecs.
+func decodeTemplate(document string) (*gcp.NotebookRuntimeTemplate, []string, []string) {
+
+	var root yaml.Node
+
+	if err := yaml.Unmarshal([]byte(document), &root); err != nil {
+		return nil, []string{strings.TrimPrefix(err.Error(), "yaml: ")}, nil
+	}
+
+	if len(root.Content) == 0 {
+		return nil, []string{"the document is empty"}, nil
+	}
+
+	var template gcp.NotebookRuntimeTemplate
+
+	decoder := yaml.NewDecoder(strings.NewReader(document))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&template); err != nil {
+
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, []string{strings.TrimPrefix(err.Error(), "yaml: ")}, nil
+		}
+
+		var errs []string
+		for _, e := range typeErr.Errors {
+			errs = append(errs, yamlTypeSuffix.ReplaceAllString(e, ""))
+		}
+		return nil, errs, nil
+	}
+
+	doc := &templateDocument{root: root.Content[0]}
+
+	for _, field := range unsupportedTemplateFields {
+		if doc.has(field) {
+			doc.errorf([]string{field}, "%s is set by the API and isn't an argument of daw_notebook", field)
+		}
+	}
+
+	if template.DisplayName == nil || *template.DisplayName == "" {
+		doc.errorf([]string{"displayName"}, "displayName is required")
+	}
+
+	machineSpec, _, networkSpec, idleShutdownConfig := decodedSpecs(&template)
+
+	if spec := template.MachineSpec; spec == nil {
+		doc.errorf([]string{"machineSpec"}, "machineSpec is required")
+	} else {
+		if spec.MachineType == nil {
+			doc.errorf([]string{"machineSpec", "machineType"}, "machineSpec.machineType is required")
+		}
+
+		// the provider overrides aren't available to functions
+		for _, d := range validateMachineSpec(builtinAcceleratorCompatibility, false, *machineSpec) {
+			at := path.Root("machine_spec")
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				at = withPath.Path()
+			}
+			doc.errorf(documentPath(at), "%s: %s", d.Summary(), d.Detail())
+		}
+	}
+
+	if spec := template.DataPersistentDiskSpec; spec == nil {
+		doc.errorf([]string{"dataPersistentDiskSpec"}, "dataPersistentDiskSpec is required")
+	} else {
+		if spec.DiskType == nil {
+			doc.errorf([]string{"dataPersistentDiskSpec", "diskType"}, "dataPersistentDiskSpec.diskType is required")
+		}
+		if spec.DiskSizeGb == nil {
+			doc.errorf([]string{"dataPersistentDiskSpec", "diskSizeGb"}, "dataPersistentDiskSpec.diskSizeGb is required")
+		}
+	}
+
+	if spec := template.NetworkSpec; spec != nil {
+		if spec.EnableInternetAccess == nil {
+			doc.errorf([]string{"networkSpec", "enableInternetAccess"}, "networkSpec.enableInternetAccess is required")
+		}
+		if spec.Network == nil {
+			doc.errorf([]string{"networkSpec", "network"}, "networkSpec.network is required")
+		}
+	}
+
+	// the rules daw_notebook only warns about
+	var warnings []string
+	for _, w := range checkSpecs(machineSpec, networkSpec, idleShutdownConfig) {
+		warnings = append(warnings, doc.linef(documentPath(w.path), "%s: %s", w.summary, w.detail))
+	}
+
+	return &template, doc.errs, warnings
+}
+
+// decodedSpecs converts the specs of the template, missing ones are nil
+func decodedSpecs(template *gcp.NotebookRuntimeTemplate) (*notebookMachineSpecModel, *notebookDataPersistentDiskSpecModel, *notebookNetworkSpecModel, *notebookIdleShutdownConfigModel) {
+
+	var (
+		machineSpec        *notebookMachineSpecModel
+		diskSpec           *notebookDataPersistentDiskSpecModel
+		networkSpec        *notebookNetworkSpecModel
+		idleShutdownConfig *notebookIdleShutdownConfigModel
+	)
+
+	if spec := template.MachineSpec; spec != nil {
+		machineSpec = &notebookMachineSpecModel{
+			MachineType:      types.StringPointerValue(spec.MachineType),
+			AcceleratorType:  types.StringPointerValue(spec.AcceleratorType),
+			AcceleratorCount: types.Int64PointerValue(spec.AcceleratorCount),
+		}
+	}
+
+	if spec := template.DataPersistentDiskSpec; spec != nil {
+		diskSpec = &notebookDataPersistentDiskSpecModel{
+			DiskType:   types.StringPointerValue(spec.DiskType),
+			DiskSizeGb: types.StringPointerValue(spec.DiskSizeGb),
+		}
+	}
+
+	if spec := template.NetworkSpec; spec != nil {
+		networkSpec = &notebookNetworkSpecModel{
+			EnableInternetAccess: types.BoolPointerValue(spec.EnableInternetAccess),
+			Network:              types.StringPointerValue(spec.Network),
+			Subnetwork:           types.StringPointerValue(spec.Subnetwork),
+		}
+	}
+
+	if config := template.IdleShutdownConfig; config != nil {
+		idleShutdownConfig = &notebookIdleShutdownConfigModel{
+			IdleTimeout:          types.StringPointerValue(config.IdleTimeout),
+			IdleShutdownDisabled: types.BoolPointerValue(config.IdleShutdownDisabled),
+		}
+	}
+
+	return machineSpec, diskSpec, networkSpec, idleShutdownConfig
+}
+
+// documentPath converts the path of a daw_notebook attribute into the fields
+// of the document, e.g. machine_spec.accelerator_count to machineSpec.acceleratorCount
+func documentPath(at path.Path) []string {
+
+	var fields []string
+
+	for _, step := range at.Steps() {
+		name, ok := step.(path.PathStepAttributeName)
+		if !ok {
+			break
+		}
+
+		parts := strings.Split(string(name), "_")
+		for i := 1; i < len(parts); i++ {
+			if parts[i] != "" {
+				parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+			}
+		}
+		fields = append(fields, strings.Join(parts, ""))
+	}
+	return fields
+}
+
+// templateDocument locates fields of the parsed document so errors can
+// point at their line
+type templateDocument struct {
+	root *yaml.Node
+	errs []string
+}
+
+// find returns the key node of the field at path, or the closest parent
+// present when it's missing
+func (d *templateDocument) find(path []string) (*yaml.Node, bool) {
+
+	node, found := d.root, d.root
+
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return found, false
+		}
+
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				found, next = node.Content[i], node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return found, false
+		}
+		node = next
+	}
+	return found, true
+}
+
+func (d *templateDocument) has(field string) bool {
+	_, ok := d.find([]string{field})
+	return ok
+}
+
+func (d *templateDocument) errorf(path []string, format string, args ...any) {
+	d.errs = append(d.errs, d.linef(path, format, args...))
+}
+
+// linef formats a message prefixed with the line of the field at path
+func (d *templateDocument) linef(path []string, format string, args ...any) string {
+
+	node, _ := d.find(path)
+	return fmt.Sprintf("line %d: ", node.Line) + fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeTemplate(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		document string
+		want     []string
+		warnings []string
+	}{
+		{
+			name: "valid",
+			document: `displayName: gpu
+machineSpec:
+  machineType: n1-standard-4
+  acceleratorType: NVIDIA_TESLA_T4
+  acceleratorCount: 1
+dataPersistentDiskSpec:
+  diskType: pd-standard
+  diskSizeGb: "100"
+idleShutdownConfig:
+  idleTimeout: 3600s
+`,
+		},
+		{
+			name:     "json",
+			document: `{"displayName": "cpu", "machineSpec": {"machineType": "n1-standard-4"}, "dataPersistentDiskSpec": {"diskType": "pd-standard", "diskSizeGb": "100"}}`,
+		},
+		{
+			name: "unknown field",
+			document: `displayName: gpu
+machineSpec:
+  machineType: n1-standard-4
+  bogus: true
+`,
+			want: []string{"line 4: field bogus not found"},
+		},
+		{
+			name: "missing fields point at their parent",
+			document: `displayName: gpu
+machineSpec:
+  acceleratorType: NVIDIA_TESLA_T4
+`,
+			want: []string{
+				"line 2: machineSpec.machineType is required",
+				"line 1: dataPersistentDiskSpec is required",
+			},
+		},
+		{
+			name: "field set by the API",
+			document: `displayName: gpu
+name: projects/p/locations/l/notebookRuntimeTemplates/t
+machineSpec:
+  machineType: n1-standard-4
+dataPersistentDiskSpec:
+  diskType: pd-standard
+  diskSizeGb: "100"
+`,
+			want: []string{"line 2: name is set by the API and isn't an argument of daw_notebook"},
+		},
+		{
+			name: "accelerator count errors point at the count",
+			document: `displayName: gpu
+machineSpec:
+  machineType: g2-standard-48
+  acceleratorType: NVIDIA_L4
+  acceleratorCount: 3
+dataPersistentDiskSpec:
+  diskType: pd-standard
+  diskSizeGb: "100"
+`,
+			want: []string{"line 5: Unsupported accelerator count: NVIDIA_L4 can't be attached 3 times, expected one of 1, 2, 4, 8"},
+		},
+		{
+			name: "accelerator type errors point at the type",
+			document: `displayName: gpu
+machineSpec:
+  machineType: e2-standard-4
+  acceleratorType: NVIDIA_TESLA_T4
+dataPersistentDiskSpec:
+  diskType: pd-standard
+  diskSizeGb: "100"
+`,
+			want: []string{"line 4: Accelerator type not supported by machine type: NVIDIA_TESLA_T4 can't be attached to e2-standard-4, it only attaches to n1 machine types"},
+		},
+		{
+			name: "idle timeout",
+			document: `displayName: gpu
+machineSpec:
+  machineType: n1-standard-4
+dataPersistentDiskSpec:
+  diskType: pd-standard
+  diskSizeGb: "100"
+idleShutdownConfig:
+  idleTimeout: 60s
+`,
+			warnings: []string{"line 8: idle_timeout must end in 's' and be a valid integer between 600 and 86400: Expected idle_timeout to be between 600 and 86400"},
+		},
+		{
+			name: "network without internet access",
+			document: `displayName: gpu
+machineSpec:
+  machineType: n1-standard-4
+dataPersistentDiskSpec:
+  diskType: pd-standard
+  diskSizeGb: "100"
+networkSpec:
+  enableInternetAccess: false
+  network: projects/p/global/networks/n
+`,
+			warnings: []string{"line 7: subnetwork can't be nil if enable_internet_access is false: Expected subnetwork to be configured"},
+		},
+		{
+			name: "accelerator count without a type",
+			document: `displayName: cpu
+machineSpec:
+  machineType: n1-standard-4
+  acceleratorCount: 1
+dataPersistentDiskSpec:
+  diskType: pd-standard
+  diskSizeGb: "100"
+`,
+			warnings: []string{"line 4: accelerator_count must be nil if accelerator_type is nil: Expected accelerator_count to not be configured"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, errs, warnings := decodeTemplate(tt.document)
+
+			if !reflect.DeepEqual(errs, tt.want) {
+				t.Fatalf("expected errors %q, got %q", tt.want, errs)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Fatalf("expected warnings %q, got %q", tt.warnings, warnings)
+			}
+		})
+	}
+}
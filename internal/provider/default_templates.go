@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultTemplates records, while planning, which resource makes a template
+// the default of each location so a second one can be rejected. The
+// provider is started afresh for every plan, claims don't outlive it.
+type defaultTemplates struct {
+	mu     sync.Mutex
+	claims map[string]string
+
+	// numbers claimants whose template isn't known yet
+	unknown int
+}
+
+func newDefaultTemplates() *defaultTemplates {
+	return &defaultTemplates{
+		claims: make(map[string]string),
+	}
+}
+
+// claim records claimant as making the default of the location, failing
+// with the earlier claimant when it is a different one. Claiming twice with
+// the same claimant succeeds, resources can be planned more than once.
+func (d *defaultTemplates) claim(project string, location string, claimant string) error {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := defaultTemplateLockKey(project, location)
+
+	if existing, ok := d.claims[key]; ok && existing != claimant {
+		return fmt.Errorf("%s can't make the default template of %s/%s, %s already does and a location only has one default", claimant, project, location, existing)
+	}
+	d.claims[key] = claimant
+	return nil
+}
+
+// claimant names the resource claiming a default by the template it makes the
+// default. While that isn't known every call returns a new claimant, two
+// resources that can't be told apart must not share a claim.
+func (d *defaultTemplates) claimant(resourceType string, template types.String) string {
+
+	if !template.IsNull() && !template.IsUnknown() {
+		return fmt.Sprintf("%s for %s", resourceType, template.ValueString())
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unknown++
+	return fmt.Sprintf("%s for a template known after apply (#%d)", resourceType, d.unknown)
+}
+
+// defaultTemplateLockKey serialises changes to the default template of a location
+func defaultTemplateLockKey(project string, location string) string {
+	return fmt.Sprintf("default-template/%s/%s", project, location)
+}
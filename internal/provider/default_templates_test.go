@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDefaultTemplatesClaim(t *testing.T) {
+
+	type claim struct {
+		location     string
+		resourceType string
+		template     types.String
+		wantErr      bool
+	}
+
+	tests := []struct {
+		name   string
+		claims []claim
+	}{
+		{
+			name: "same claimant twice",
+			claims: []claim{
+				{location: "us-central1", resourceType: "daw_notebook", template: types.StringValue("t1")},
+				{location: "us-central1", resourceType: "daw_notebook", template: types.StringValue("t1")},
+			},
+		},
+		{
+			name: "two claimants with unknown templates",
+			claims: []claim{
+				{location: "us-central1", resourceType: "daw_notebook_default", template: types.StringUnknown()},
+				{location: "us-central1", resourceType: "daw_notebook_default", template: types.StringUnknown(), wantErr: true},
+			},
+		},
+		{
+			name: "daw_notebook and daw_notebook_default on one location",
+			claims: []claim{
+				{location: "us-central1", resourceType: "daw_notebook", template: types.StringValue("t1")},
+				{location: "us-central1", resourceType: "daw_notebook_default", template: types.StringValue("t1"), wantErr: true},
+			},
+		},
+		{
+			name: "different locations",
+			claims: []claim{
+				{location: "us-central1", resourceType: "daw_notebook", template: types.StringValue("t1")},
+				{location: "europe-west4", resourceType: "daw_notebook_default", template: types.StringValue("t2")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			defaults := newDefaultTemplates()
+
+			for i, c := range tt.claims {
+
+				err := defaults.claim("p", c.location, defaults.claimant(c.resourceType, c.template))
+
+				if c.wantErr && err == nil {
+					t.Fatalf("claim %d: expected an error", i)
+				}
+				if !c.wantErr && err != nil {
+					t.Fatalf("claim %d: unexpected error: %v", i, err)
+				}
+			}
+		})
+	}
+}
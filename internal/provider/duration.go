@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseDurationSeconds parses the API representation of a duration, a whole
+// number of seconds ending in 's' (e.g. "3600s")
+func parseDurationSeconds(value string) (time.Duration, error) {
+
+	if !strings.HasSuffix(value, "s") {
+		return 0, fmt.Errorf("expected %q to end in 's'", value)
+	}
+
+	seconds, err := strconv.ParseInt(strings.TrimSuffix(value, "s"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected %q to be a whole number of seconds", value)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// formatDurationSeconds converts a duration like "2h" or "1h30m" into the
+// API representation, which only takes whole seconds
+func formatDurationSeconds(value string) (string, error) {
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return "", err
+	}
+
+	if duration < 0 || duration%time.Second != 0 {
+		return "", fmt.Errorf("expected %q to be a whole, non-negative number of seconds", value)
+	}
+	return fmt.Sprintf("%ds", int64(duration/time.Second)), nil
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &durationSecondsFunction{}
+
+// durationSecondsFunction converts readable durations into the seconds the
+// API takes, e.g. for idle_timeout
+type durationSecondsFunction struct{}
+
+func NewDurationSecondsFunction() function.Function {
+	return &durationSecondsFunction{}
+}
+
+func (f *durationSecondsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "duration_seconds"
+}
+
+func (f *durationSecondsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Convert a duration into seconds",
+		MarkdownDescription: "Converts a duration like `\"2h\"` or `\"1h30m\"` into the API representation, e.g. `\"7200s\"`. Units are `h`, `m` and `s`, the result has to be a whole number of seconds.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "duration",
+				MarkdownDescription: "The duration, in the format of Go's `time.ParseDuration`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *durationSecondsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+
+	var duration string
+
+	resp.Error = req.Arguments.Get(ctx, &duration)
+	if resp.Error != nil {
+		return
+	}
+
+	seconds, err := formatDurationSeconds(duration)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, seconds)
+}
@@ -0,0 +1,41 @@
+package provider
+
+import "testing"
+
+func TestFormatDurationSeconds(t *testing.T) {
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "2h", want: "7200s"},
+		{value: "1h30m", want: "5400s"},
+		{value: "45s", want: "45s"},
+		{value: "0s", want: "0s"},
+		{value: "1.5s", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "3600", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+
+			got, err := formatDurationSeconds(tt.value)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// guardrails are the organisation policies every daw_notebook must satisfy,
+// zero values switch a rule off
+type guardrails struct {
+	requireKmsKey           bool
+	allowedMachineTypes     []string
+	maxAcceleratorCount     int64
+	allowedAcceleratorTypes []string
+	forbidInternetAccess    bool
+	requiredLabels          []string
+	maxDiskSizeGb           int64
+	requireIdleShutdown     bool
+}
+
+// newGuardrails converts the provider configuration, nil when not configured
+func newGuardrails(ctx context.Context, model *guardrailsModel) (*guardrails, diag.Diagnostics) {
+
+	var diags diag.Diagnostics
+
+	if model == nil {
+		return nil, diags
+	}
+
+	g := &guardrails{
+		requireKmsKey:        model.RequireKmsKey.ValueBool(),
+		maxAcceleratorCount:  model.MaxAcceleratorCount.ValueInt64(),
+		forbidInternetAccess: model.ForbidInternetAccess.ValueBool(),
+		maxDiskSizeGb:        model.MaxDiskSizeGb.ValueInt64(),
+		requireIdleShutdown:  model.RequireIdleShutdown.ValueBool(),
+	}
+
+	if !model.AllowedMachineTypes.IsNull() {
+		diags.Append(model.AllowedMachineTypes.ElementsAs(ctx, &g.allowedMachineTypes, false)...)
+	}
+	if !model.AllowedAcceleratorTypes.IsNull() {
+		diags.Append(model.AllowedAcceleratorTypes.ElementsAs(ctx, &g.allowedAcceleratorTypes, false)...)
+	}
+	if !model.RequiredLabels.IsNull() {
+		diags.Append(model.RequiredLabels.ElementsAs(ctx, &g.requiredLabels, false)...)
+	}
+
+	for _, pattern := range g.allowedMachineTypes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			diags.AddAttributeError(
+				tfpath.Root("guardrails").AtName("allowed_machine_types"),
+				"Invalid machine type pattern",
+				fmt.Sprintf("%q is not a valid pattern: %s", pattern, err.Error()),
+			)
+		}
+	}
+
+	return g, diags
+}
+
+// check reports every rule the template violates. Unknown values are skipped,
+// they are checked again once known.
+func (g *guardrails) check(data notebookModel, labels map[string]string, labelsKnown bool) diag.Diagnostics {
+
+	var diags diag.Diagnostics
+
+	if g == nil {
+		return diags
+	}
+
+	violation := func(at tfpath.Path, rule string, detail string) {
+		diags.AddAttributeError(at, "Guardrail violated: "+rule, detail+". This rule is set by the provider guardrails.")
+	}
+
+	if g.requireKmsKey && data.KmsKeyName.IsNull() {
+		violation(tfpath.Root("kms_key_name"), "require_kms_key", "Templates must be encrypted with a customer managed key, set kms_key_name")
+	}
+
+	// the specs are nil until given by the configuration or a preset
+	machineSpec := valueOrZero(data.MachineSpec)
+
+	machineType := machineSpec.MachineType
+	if len(g.allowedMachineTypes) > 0 && !machineType.IsNull() && !machineType.IsUnknown() && !matchesAny(g.allowedMachineTypes, machineType.ValueString()) {
+		violation(tfpath.Root("machine_spec").AtName("machine_type"), "allowed_machine_types",
+			fmt.Sprintf("%s is not an allowed machine type, expected one of %s", machineType.ValueString(), strings.Join(g.allowedMachineTypes, ", ")))
+	}
+
+	acceleratorType := machineSpec.AcceleratorType
+	if !acceleratorType.IsNull() && !acceleratorType.IsUnknown() {
+
+		if len(g.allowedAcceleratorTypes) > 0 && !matchesAny(g.allowedAcceleratorTypes, acceleratorType.ValueString()) {
+			violation(tfpath.Root("machine_spec").AtName("accelerator_type"), "allowed_accelerator_types",
+				fmt.Sprintf("%s is not an allowed accelerator type, expected one of %s", acceleratorType.ValueString(), strings.Join(g.allowedAcceleratorTypes, ", ")))
+		}
+
+		count := int64(1)
+		if !machineSpec.AcceleratorCount.IsNull() {
+			count = machineSpec.AcceleratorCount.ValueInt64()
+		}
+
+		if g.maxAcceleratorCount > 0 && !machineSpec.AcceleratorCount.IsUnknown() && count > g.maxAcceleratorCount {
+			violation(tfpath.Root("machine_spec").AtName("accelerator_count"), "max_accelerator_count",
+				fmt.Sprintf("%d accelerators requested, at most %d are allowed", count, g.maxAcceleratorCount))
+		}
+	}
+
+	if g.forbidInternetAccess && valueOrZero(data.NetworkSpec).EnableInternetAccess.ValueBool() {
+		violation(tfpath.Root("network_spec").AtName("enable_internet_access"), "forbid_internet_access",
+			"Runtimes must not have internet access, set enable_internet_access to false")
+	}
+
+	if labelsKnown {
+		for _, key := range g.requiredLabels {
+			if _, ok := labels[key]; !ok {
+				violation(tfpath.Root("labels"), "required_labels",
+					fmt.Sprintf("The label %q is required, set it in labels or the provider default_labels", key))
+			}
+		}
+	}
+
+	diskSize := valueOrZero(data.DataPersistentDiskSpec).DiskSizeGb
+	if g.maxDiskSizeGb > 0 && !diskSize.IsNull() && !diskSize.IsUnknown() {
+		if size, err := strconv.ParseInt(diskSize.ValueString(), 10, 64); err == nil && size > g.maxDiskSizeGb {
+			violation(tfpath.Root("data_persistent_disk_spec").AtName("disk_size_gb"), "max_disk_size_gb",
+				fmt.Sprintf("A %d GB disk was requested, at most %d GB is allowed", size, g.maxDiskSizeGb))
+		}
+	}
+
+	if g.requireIdleShutdown && valueOrZero(data.IdleShutdownConfig).IdleShutdownDisabled.ValueBool() {
+		violation(tfpath.Root("idle_shutdown_config").AtName("idle_shutdown_disabled"), "require_idle_shutdown",
+			"Runtimes must shut down when idle, set idle_shutdown_disabled to false")
+	}
+
+	return diags
+}
+
+// matchesAny reports whether value matches one of the patterns, e.g. "n1-*"
+func matchesAny(patterns []string, value string) bool {
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// guardrailLabels are the labels the template ends up with, the configured
+// labels merged over the provider default_labels
+func guardrailLabels(ctx context.Context, defaults map[string]string, configured types.Map) (map[string]string, bool, diag.Diagnostics) {
+
+	if configured.IsUnknown() {
+		return nil, false, nil
+	}
+
+	labels, diags := labelsFromValue(ctx, configured)
+	return mergeLabels(defaults, labels), true, diags
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestGuardrailsCheck(t *testing.T) {
+
+	g := &guardrails{
+		requireKmsKey:           true,
+		allowedMachineTypes:     []string{"n1-*", "g2-standard-*"},
+		maxAcceleratorCount:     2,
+		allowedAcceleratorTypes: []string{"NVIDIA_TESLA_T4", "NVIDIA_L4"},
+		forbidInternetAccess:    true,
+		requiredLabels:          []string{"team"},
+		maxDiskSizeGb:           500,
+		requireIdleShutdown:     true,
+	}
+
+	compliant := func() notebookModel {
+		return notebookModel{
+			KmsKeyName: types.StringValue("projects/p/locations/l/keyRings/r/cryptoKeys/k"),
+			MachineSpec: &notebookMachineSpecModel{
+				MachineType:      types.StringValue("n1-standard-4"),
+				AcceleratorType:  types.StringValue("NVIDIA_TESLA_T4"),
+				AcceleratorCount: types.Int64Value(1),
+			},
+			DataPersistentDiskSpec: &notebookDataPersistentDiskSpecModel{
+				DiskType:   types.StringValue("pd-standard"),
+				DiskSizeGb: types.StringValue("100"),
+			},
+			NetworkSpec: &notebookNetworkSpecModel{
+				EnableInternetAccess: types.BoolValue(false),
+			},
+			IdleShutdownConfig: &notebookIdleShutdownConfigModel{
+				IdleTimeout:          types.StringValue("3600s"),
+				IdleShutdownDisabled: types.BoolValue(false),
+			},
+		}
+	}
+
+	labels := map[string]string{"team": "data"}
+
+	tests := []struct {
+		name        string
+		modify      func(data *notebookModel)
+		labels      map[string]string
+		labelsKnown bool
+		want        string
+	}{
+		{
+			name:        "compliant",
+			modify:      func(data *notebookModel) {},
+			labels:      labels,
+			labelsKnown: true,
+		},
+		{
+			name:        "kms key missing",
+			modify:      func(data *notebookModel) { data.KmsKeyName = types.StringNull() },
+			labels:      labels,
+			labelsKnown: true,
+			want:        "Guardrail violated: require_kms_key",
+		},
+		{
+			name:        "machine type not allowed",
+			modify:      func(data *notebookModel) { data.MachineSpec.MachineType = types.StringValue("e2-standard-4") },
+			labels:      labels,
+			labelsKnown: true,
+			want:        "Guardrail violated: allowed_machine_types",
+		},
+		{
+			name:        "unknown machine type is skipped",
+			modify:      func(data *notebookModel) { data.MachineSpec.MachineType = types.StringUnknown() },
+			labels:      labels,
+			labelsKnown: true,
+		},
+		{
+			name:        "accelerator type not allowed",
+			modify:      func(data *notebookModel) { data.MachineSpec.AcceleratorType = types.StringValue("NVIDIA_TESLA_V100") },
+			labels:      labels,
+			labelsKnown: true,
+			want:        "Guardrail violated: allowed_accelerator_types",
+		},
+		{
+			name:        "too many accelerators",
+			modify:      func(data *notebookModel) { data.MachineSpec.AcceleratorCount = types.Int64Value(4) },
+			labels:      labels,
+			labelsKnown: true,
+			want:        "Guardrail violated: max_accelerator_count",
+		},
+		{
+			name:        "internet access",
+			modify:      func(data *notebookModel) { data.NetworkSpec.EnableInternetAccess = types.BoolValue(true) },
+			labels:      labels,
+			labelsKnown: true,
+			want:        "Guardrail violated: forbid_internet_access",
+		},
+		{
+			name:        "required label missing",
+			modify:      func(data *notebookModel) {},
+			labels:      map[string]string{},
+			labelsKnown: true,
+			want:        "Guardrail violated: required_labels",
+		},
+		{
+			name:   "unknown labels are skipped",
+			modify: func(data *notebookModel) {},
+		},
+		{
+			name:        "disk too large",
+			modify:      func(data *notebookModel) { data.DataPersistentDiskSpec.DiskSizeGb = types.StringValue("1000") },
+			labels:      labels,
+			labelsKnown: true,
+			want:        "Guardrail violated: max_disk_size_gb",
+		},
+		{
+			name:        "idle shutdown disabled",
+			modify:      func(data *notebookModel) { data.IdleShutdownConfig.IdleShutdownDisabled = types.BoolValue(true) },
+			labels:      labels,
+			labelsKnown: true,
+			want:        "Guardrail violated: require_idle_shutdown",
+		},
+		{
+			name: "specs given by a preset are skipped until merged",
+			modify: func(data *notebookModel) {
+				data.MachineSpec = nil
+				data.DataPersistentDiskSpec = nil
+				data.IdleShutdownConfig = nil
+			},
+			labels:      labels,
+			labelsKnown: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			data := compliant()
+			tt.modify(&data)
+
+			diags := g.check(data, tt.labels, tt.labelsKnown)
+
+			if tt.want == "" {
+				if diags.HasError() {
+					t.Fatalf("expected no errors, got %v", diags)
+				}
+				return
+			}
+
+			if len(diags) != 1 || diags[0].Summary() != tt.want {
+				t.Fatalf("expected a single %q error, got %v", tt.want, diags)
+			}
+		})
+	}
+}
+
+func TestGuardrailsCheckNil(t *testing.T) {
+
+	var g *guardrails
+
+	if diags := g.check(notebookModel{}, nil, true); diags.HasError() {
+		t.Fatalf("expected no guardrails to pass everything, got %v", diags)
+	}
+}
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// how many times a policy change is retried when the etag is stale
+const iamPolicyRetries = 5
+
+// modifyTemplatePolicy performs an etag guarded read-modify-write of the IAM
+// policy of the template, retrying when another change raced us
+func (d *providerData) modifyTemplatePolicy(template string, modify func(policy *gcp.Policy) error) (*gcp.Policy, error) {
+
+	client, err := d.templateClient(template)
+	if err != nil {
+		return nil, err
+	}
+
+	d.locks.Lock(template)
+	defer d.locks.Unlock(template)
+
+	for attempt := 1; ; attempt++ {
+
+		policy, err := client.GetIamPolicy(template)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := modify(policy); err != nil {
+			return nil, err
+		}
+
+		updated, err := client.SetIamPolicy(template, policy)
+
+		if gcp.IsConflict(err) && attempt < iamPolicyRetries {
+			continue
+		}
+		return updated, err
+	}
+}
+
+// iamConditionExpr converts the condition of a binding or member
+func iamConditionExpr(condition *notebookIamConditionModel) *gcp.Expr {
+
+	if condition == nil {
+		return nil
+	}
+	return &gcp.Expr{
+		Title:       condition.Title.ValueString(),
+		Description: condition.Description.ValueString(),
+		Expression:  condition.Expression.ValueString(),
+	}
+}
+
+// iamConditionModel converts the condition of a policy binding
+func iamConditionModel(expr *gcp.Expr) *notebookIamConditionModel {
+
+	if expr == nil {
+		return nil
+	}
+
+	condition := &notebookIamConditionModel{
+		Title:       types.StringValue(expr.Title),
+		Description: types.StringNull(),
+		Expression:  types.StringValue(expr.Expression),
+	}
+	if expr.Description != "" {
+		condition.Description = types.StringValue(expr.Description)
+	}
+	return condition
+}
+
+// iamConditionMatches compares the condition of a binding with the one of a
+// resource. After an import only the title of the condition is known.
+func iamConditionMatches(condition *notebookIamConditionModel, expr *gcp.Expr) bool {
+
+	if condition == nil || expr == nil {
+		return condition == nil && expr == nil
+	}
+	if condition.Expression.IsNull() {
+		return condition.Title.ValueString() == expr.Title
+	}
+	return *iamConditionExpr(condition) == *expr
+}
+
+// findIamBinding returns the index of the binding of role with the condition, or -1
+func findIamBinding(policy *gcp.Policy, role string, condition *notebookIamConditionModel) int {
+
+	for i, binding := range policy.Bindings {
+		if binding.Role == role && iamConditionMatches(condition, binding.Condition) {
+			return i
+		}
+	}
+	return -1
+}
+
+// removeEmptyIamBindings drops bindings left without members
+func removeEmptyIamBindings(policy *gcp.Policy) {
+
+	bindings := policy.Bindings[:0]
+	for _, binding := range policy.Bindings {
+		if len(binding.Members) > 0 {
+			bindings = append(bindings, binding)
+		}
+	}
+	policy.Bindings = bindings
+}
+
+// iamPolicyData is the JSON form of the bindings used by policy_data
+type iamPolicyData struct {
+	Bindings []gcp.Binding `json:"bindings,omitempty"`
+}
+
+// parseIamPolicyData decodes policy_data into its bindings
+func parseIamPolicyData(data string) ([]gcp.Binding, error) {
+
+	var policy iamPolicyData
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		return nil, fmt.Errorf("policy_data must be a JSON IAM policy: %w", err)
+	}
+	return policy.Bindings, nil
+}
+
+// normaliseIamPolicyData encodes the bindings in a stable order so policies
+// can be compared
+func normaliseIamPolicyData(bindings []gcp.Binding) (string, error) {
+
+	normalised := make([]gcp.Binding, 0, len(bindings))
+	for _, binding := range bindings {
+		if len(binding.Members) == 0 {
+			continue
+		}
+		members := append([]string{}, binding.Members...)
+		sort.Strings(members)
+		binding.Members = members
+		normalised = append(normalised, binding)
+	}
+
+	sort.SliceStable(normalised, func(i, j int) bool {
+		return iamBindingKey(normalised[i]) < iamBindingKey(normalised[j])
+	})
+
+	data, err := json.Marshal(iamPolicyData{Bindings: normalised})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func iamBindingKey(binding gcp.Binding) string {
+
+	key := binding.Role
+	if binding.Condition != nil {
+		key = strings.Join([]string{key, binding.Condition.Title, binding.Condition.Description, binding.Condition.Expression}, "\x00")
+	}
+	return key
+}
+
+// iamConditionSchema is the condition shared by bindings and members
+func iamConditionSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: "An IAM condition restricting when the role applies",
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplace(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"title": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"expression": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "A CEL expression, e.g. `request.time < timestamp(\"2025-01-01T00:00:00Z\")`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// splitIamImportID splits an import id made of space separated parts, the
+// condition title (if any) being the remainder
+func splitIamImportID(id string, parts int) ([]string, string, error) {
+
+	fields := strings.SplitN(strings.TrimSpace(id), " ", parts+1)
+
+	if len(fields) < parts {
+		return nil, "", fmt.Errorf("expected %d space separated parts, got: %q", parts, id)
+	}
+	if len(fields) == parts {
+		return fields, "", nil
+	}
+	return fields[:parts], strings.TrimSpace(fields[parts]), nil
+}
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Labels follow the google provider model:
+//   - labels are the non-authoritative labels in the configuration
+//   - terraform_labels are the labels plus the provider default_labels, these
+//     are what gets sent to the API
+//   - effective_labels are all the labels present on the template, including
+//     ones applied outside of Terraform
+
+// ignoreLabels matches labels applied by GCP or other tooling, these are
+// dropped from state and never sent to the API
+type ignoreLabels struct {
+	keys     []string
+	prefixes []string
+}
+
+func (i ignoreLabels) ignored(key string) bool {
+
+	for _, k := range i.keys {
+		if key == k {
+			return true
+		}
+	}
+	for _, prefix := range i.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns labels without the ignored keys
+func (i ignoreLabels) filter(labels map[string]string) map[string]string {
+
+	filtered := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if !i.ignored(k) {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+// mergeLabels overlays labels on top of defaults, labels win on conflicts
+func mergeLabels(defaults map[string]string, labels map[string]string) map[string]string {
+
+	merged := make(map[string]string, len(defaults)+len(labels))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+// labelsMapValue converts labels into a map value, no labels being null
+func labelsMapValue(ctx context.Context, labels map[string]string) (types.Map, diag.Diagnostics) {
+
+	if len(labels) == 0 {
+		return types.MapNull(types.StringType), nil
+	}
+	return types.MapValueFrom(ctx, types.StringType, labels)
+}
+
+// labelsFromValue converts a known map value into labels
+func labelsFromValue(ctx context.Context, value types.Map) (map[string]string, diag.Diagnostics) {
+
+	labels := make(map[string]string)
+
+	if value.IsNull() || value.IsUnknown() {
+		return labels, nil
+	}
+	diags := value.ElementsAs(ctx, &labels, false)
+	return labels, diags
+}
+
+// refreshLabels keeps the actual value of the keys tracked in prior, so labels
+// added outside of Terraform don't show up as drift. A null or empty prior is
+// kept as it is.
+func refreshLabels(ctx context.Context, prior types.Map, actual map[string]string) (types.Map, diag.Diagnostics) {
+
+	if prior.IsNull() || prior.IsUnknown() {
+		return prior, nil
+	}
+
+	tracked, diags := labelsFromValue(ctx, prior)
+	if diags.HasError() || len(tracked) == 0 {
+		return prior, diags
+	}
+
+	refreshed := make(map[string]string)
+	for k := range tracked {
+		if v, ok := actual[k]; ok {
+			refreshed[k] = v
+		}
+	}
+
+	value, d := labelsMapValue(ctx, refreshed)
+	diags.Append(d...)
+	return value, diags
+}
+
+// planLabels computes terraform_labels from the configured labels and the
+// provider default_labels, less any ignored labels. Labels can't be updated in
+// place, so the template is replaced when a label is missing from (or differs
+// on) the template or a label Terraform previously managed is removed.
+func planLabels(ctx context.Context, defaultLabels map[string]string, ignore ignoreLabels, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	var labels types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("labels"), &labels)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if labels.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("terraform_labels"), types.MapUnknown(types.StringType))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_labels"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	configured, diags := labelsFromValue(ctx, labels)
+	resp.Diagnostics.Append(diags...)
+
+	for k := range configured {
+		if ignore.ignored(k) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("labels").AtMapKey(k),
+				"Label is ignored by the provider",
+				fmt.Sprintf("The label %q matches the provider ignore_labels, it would never be sent to the API or kept in state. Remove it from labels or from ignore_labels", k),
+			)
+		}
+	}
+
+	terraformLabels, diags := labelsMapValue(ctx, ignore.filter(mergeLabels(defaultLabels, configured)))
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("terraform_labels"), terraformLabels)...)
+
+	// nothing else to work out on create
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var priorTerraformLabels, effectiveLabels types.Map
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("terraform_labels"), &priorTerraformLabels)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("effective_labels"), &effectiveLabels)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, diags := labelsFromValue(ctx, terraformLabels)
+	resp.Diagnostics.Append(diags...)
+	prior, diags := labelsFromValue(ctx, priorTerraformLabels)
+	resp.Diagnostics.Append(diags...)
+	actual, diags := labelsFromValue(ctx, effectiveLabels)
+	resp.Diagnostics.Append(diags...)
+
+	replace := false
+	for k, v := range planned {
+		if value, ok := actual[k]; !ok || value != v {
+			replace = true
+		}
+	}
+	for k := range prior {
+		if _, ok := planned[k]; !ok {
+			replace = true
+		}
+	}
+
+	if replace {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("terraform_labels"))
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_labels"), types.MapUnknown(types.StringType))...)
+	}
+}
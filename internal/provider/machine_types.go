@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"path"
+	"strings"
+)
+
+// colabMachineFamilies are the machine families Colab Enterprise runtimes can use
+var colabMachineFamilies = map[string]bool{
+	"e2":  true,
+	"n1":  true,
+	"n2":  true,
+	"n2d": true,
+	"a2":  true,
+	"g2":  true,
+}
+
+// machineFamily is the family of a machine type, e.g. "n1" for "n1-standard-4"
+func machineFamily(machineType string) string {
+	family, _, _ := strings.Cut(path.Base(machineType), "-")
+	return family
+}
+
+// vertexAcceleratorType converts a Compute Engine accelerator name (or url)
+// to the name Vertex AI uses, e.g. "nvidia-tesla-t4" to "NVIDIA_TESLA_T4"
+func vertexAcceleratorType(computeName string) string {
+	return strings.ToUpper(strings.ReplaceAll(path.Base(computeName), "-", "_"))
+}
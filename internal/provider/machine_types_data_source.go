@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &machineTypesDataSource{}
+	_ datasource.DataSourceWithConfigure = &machineTypesDataSource{}
+)
+
+// just making alias to not get confused
+type machineTypesDataSource gcpNotebookClient
+
+func NewMachineTypesDataSource() datasource.DataSource {
+	return &machineTypesDataSource{}
+}
+
+// Configure implements datasource.DataSourceWithConfigure.
+func (n *machineTypesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got %T", req.ProviderData),
+		)
+		return
+	}
+	n.provider = data
+}
+
+// Metadata implements datasource.DataSource.
+func (n *machineTypesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_machine_types"
+}
+
+// Read implements datasource.DataSource.
+func (n *machineTypesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read (machine_types_data_source) *********")
+
+	var state machineTypesDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+	state.Project = types.StringValue(project)
+	state.Location = types.StringValue(location)
+
+	client := n.provider.computeClient(project)
+
+	machineTypes, err := client.GetMachineTypes(location)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read GCP Machine Types",
+			err.Error(),
+		)
+		return
+	}
+
+	acceleratorTypes, err := client.GetAcceleratorTypes(location)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read GCP Accelerator Types",
+			err.Error(),
+		)
+		return
+	}
+
+	// the same type is listed once per zone, keep one item with every zone
+	machines := make(map[string]*machineTypeItemModel)
+	zones := make(map[string][]string)
+
+	for _, machineType := range machineTypes {
+
+		name := stringOrEmpty(machineType.Name)
+		if !colabMachineFamilies[machineFamily(name)] {
+			continue
+		}
+
+		zones[name] = append(zones[name], path.Base(stringOrEmpty(machineType.Zone)))
+
+		if _, ok := machines[name]; ok {
+			continue
+		}
+
+		item := &machineTypeItemModel{
+			Name:             types.StringValue(name),
+			Family:           types.StringValue(machineFamily(name)),
+			GuestCpus:        types.Int64PointerValue(machineType.GuestCpus),
+			MemoryMb:         types.Int64PointerValue(machineType.MemoryMb),
+			IsSharedCpu:      types.BoolValue(machineType.IsSharedCpu != nil && *machineType.IsSharedCpu),
+			AcceleratorType:  types.StringNull(),
+			AcceleratorCount: types.Int64Null(),
+		}
+
+		// a2 and g2 come with their GPUs attached
+		if len(machineType.Accelerators) > 0 {
+			item.AcceleratorType = types.StringValue(vertexAcceleratorType(stringOrEmpty(machineType.Accelerators[0].GuestAcceleratorType)))
+			item.AcceleratorCount = types.Int64PointerValue(machineType.Accelerators[0].GuestAcceleratorCount)
+		}
+		machines[name] = item
+	}
+
+	accelerators := make(map[string]*acceleratorTypeItemModel)
+
+	for _, acceleratorType := range acceleratorTypes {
+
+		computeName := stringOrEmpty(acceleratorType.Name)
+		name := vertexAcceleratorType(computeName)
+		// the accelerator types Colab Enterprise runtimes can use
+		if _, ok := builtinAcceleratorCompatibility[name]; !ok {
+			continue
+		}
+
+		zones[name] = append(zones[name], path.Base(stringOrEmpty(acceleratorType.Zone)))
+
+		if _, ok := accelerators[name]; ok {
+			continue
+		}
+
+		accelerators[name] = &acceleratorTypeItemModel{
+			Name:         types.StringValue(name),
+			ComputeName:  types.StringValue(computeName),
+			Description:  types.StringPointerValue(acceleratorType.Description),
+			MaximumCount: types.Int64PointerValue(acceleratorType.MaximumCardsPerInstance),
+		}
+	}
+
+	state.MachineTypes = []machineTypeItemModel{}
+	for name, item := range machines {
+		item.Zones, diags = types.ListValueFrom(ctx, types.StringType, zones[name])
+		resp.Diagnostics.Append(diags...)
+		state.MachineTypes = append(state.MachineTypes, *item)
+	}
+	sort.Slice(state.MachineTypes, func(i, j int) bool {
+		return state.MachineTypes[i].Name.ValueString() < state.MachineTypes[j].Name.ValueString()
+	})
+
+	state.AcceleratorTypes = []acceleratorTypeItemModel{}
+	for name, item := range accelerators {
+		item.Zones, diags = types.ListValueFrom(ctx, types.StringType, zones[name])
+		resp.Diagnostics.Append(diags...)
+		state.AcceleratorTypes = append(state.AcceleratorTypes, *item)
+	}
+	sort.Slice(state.AcceleratorTypes, func(i, j int) bool {
+		return state.AcceleratorTypes[i].Name.ValueString() < state.AcceleratorTypes[j].Name.ValueString()
+	})
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Schema implements datasource.DataSource.
+func (n *machineTypesDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema (machine_types_data_source) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The machine and accelerator types Colab Enterprise runtimes can use in a location, as reported by Compute Engine",
+		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The project to look up, defaults to the provider project",
+			},
+			"location": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The region to look up, defaults to the provider location",
+			},
+			"machine_types": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The value for `machine_spec.machine_type`, e.g. `n1-standard-4`",
+						},
+						"family": schema.StringAttribute{
+							Computed: true,
+						},
+						"guest_cpus": schema.Int64Attribute{
+							Computed: true,
+						},
+						"memory_mb": schema.Int64Attribute{
+							Computed: true,
+						},
+						"is_shared_cpu": schema.BoolAttribute{
+							Computed: true,
+						},
+						"accelerator_type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The GPU that comes with the machine type (a2 and g2)",
+						},
+						"accelerator_count": schema.Int64Attribute{
+							Computed: true,
+						},
+						"zones": schema.ListAttribute{
+							Computed:            true,
+							ElementType:         types.StringType,
+							MarkdownDescription: "The zones of the location offering the machine type",
+						},
+					},
+				},
+			},
+			"accelerator_types": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The value for `machine_spec.accelerator_type`, e.g. `NVIDIA_TESLA_T4`",
+						},
+						"compute_name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The Compute Engine name, e.g. `nvidia-tesla-t4`",
+						},
+						"description": schema.StringAttribute{
+							Computed: true,
+						},
+						"maximum_count": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The maximum `accelerator_count` for a single runtime",
+						},
+						"zones": schema.ListAttribute{
+							Computed:            true,
+							ElementType:         types.StringType,
+							MarkdownDescription: "The zones of the location offering the accelerator type",
+						},
+					},
+				},
+			},
+		},
+	}
+}
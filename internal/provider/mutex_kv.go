@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"sync"
+)
+
+// mutexKV hands out a mutex per key so changes to a shared object, like the
+// IAM policy of a template, made by several resources are serialised
+type mutexKV struct {
+	mu    sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+func newMutexKV() *mutexKV {
+	return &mutexKV{
+		store: make(map[string]*sync.Mutex),
+	}
+}
+
+func (m *mutexKV) Lock(key string) {
+	m.get(key).Lock()
+}
+
+func (m *mutexKV) Unlock(key string) {
+	m.get(key).Unlock()
+}
+
+func (m *mutexKV) get(key string) *sync.Mutex {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mutex, ok := m.store[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.store[key] = mutex
+	}
+	return mutex
+}
@@ -0,0 +1,477 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource               = &notebookContentResource{}
+	_ resource.ResourceWithConfigure  = &notebookContentResource{}
+	_ resource.ResourceWithModifyPlan = &notebookContentResource{}
+)
+
+// the file Colab Enterprise opens in a notebook repository
+const defaultNotebookContentPath = "content.ipynb"
+
+// just making alias to not get confused
+type notebookContentResource gcpNotebookClient
+
+func NewNotebookContentResource() resource.Resource {
+	return &notebookContentResource{}
+}
+
+func (n *notebookContentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	n.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (n *notebookContentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_content"
+}
+
+// Schema implements resource.Resource.
+func (n *notebookContentResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(notebook_content_resource) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A Colab Enterprise notebook, stored in a Dataform repository and committed from a local `.ipynb` file",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "The full name of the repository, usable as a `dataform_repository_resource_name`",
+			},
+			"repository_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The project to create the repository in, defaults to the provider project",
+			},
+			"location": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The location to create the repository in, defaults to the provider location",
+			},
+			"display_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The name shown in Colab Enterprise, e.g. `Getting started.ipynb`",
+			},
+			"labels": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: fmt.Sprintf("Labels of the repository, `%s` is always added so Colab Enterprise lists it", gcp.ColabRepositoryLabel),
+			},
+			"source": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The path to the local `.ipynb` file to commit",
+			},
+			"path": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultNotebookContentPath),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The path of the notebook in the repository, defaults to `" + defaultNotebookContentPath + "`",
+			},
+			"author_name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("Terraform"),
+				MarkdownDescription: "The author of the commits, defaults to `Terraform`",
+			},
+			"author_email": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("terraform@localhost"),
+				MarkdownDescription: "The email of the author of the commits, defaults to `terraform@localhost`",
+			},
+			"commit_message": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The message of the commits, defaults to `Update <path>`",
+			},
+			"content_sha256": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The SHA-256 of the committed notebook, a change in the repository or the local file commits a new revision",
+			},
+			"commit_sha": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The commit holding the current content",
+			},
+			"create_time": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan, it hashes the local
+// file so a change to it (or to the repository) shows up as an update.
+func (n *notebookContentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	tflog.Debug(ctx, "********* In ModifyPlan(notebook_content_resource) *********")
+
+	// nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var source types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, tfpath.Root("source"), &source)...)
+	if resp.Diagnostics.HasError() || source.IsUnknown() {
+		return
+	}
+
+	contents, err := readNotebookSource(source.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			tfpath.Root("source"),
+			"Invalid notebook source",
+			err.Error(),
+		)
+		return
+	}
+
+	sha := contentSha256(contents)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("content_sha256"), sha)...)
+
+	// keep the commit unless a new one is needed
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var priorSha, priorCommit types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, tfpath.Root("content_sha256"), &priorSha)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, tfpath.Root("commit_sha"), &priorCommit)...)
+
+	if priorSha.ValueString() == sha {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("commit_sha"), priorCommit)...)
+	}
+}
+
+// Create implements resource.Resource.
+func (n *notebookContentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(notebook_content_resource) *********")
+
+	var plan notebookContentModel
+	diags := req.Plan.Get(ctx, &plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	repository, diags := plan.toRepository(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+	plan.Project = types.StringValue(project)
+	plan.Location = types.StringValue(location)
+
+	client := n.provider.dataformClient(project, location)
+
+	created, err := client.CreateRepository(plan.RepositoryId.ValueString(), repository)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating notebook repository",
+			"Could not create Dataform repository, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// save what we have so a failed commit doesn't orphan the repository,
+	// an empty hash makes the next apply commit again
+	plan.Name = types.StringPointerValue(created.Name)
+	plan.CreateTime = types.StringPointerValue(created.CreateTime)
+	contentSha := plan.ContentSha256
+	plan.ContentSha256 = types.StringValue("")
+	plan.CommitSha = types.StringNull()
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	commitSha, err := n.commit(client, plan, contentSha.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error committing notebook",
+			"Could not commit notebook content, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.ContentSha256 = contentSha
+	plan.CommitSha = types.StringValue(commitSha)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read implements resource.Resource.
+func (n *notebookContentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(notebook_content_resource) *********")
+
+	var state notebookContentModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+	client := n.provider.dataformClient(project, location)
+
+	repository, err := client.GetRepository(state.Name.ValueString())
+
+	if gcp.IsNotFound(err) {
+		tflog.Warn(ctx, "Notebook repository no longer exists, removing from state", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading GCP Notebook Repository",
+			"Could not read Dataform repository with name "+state.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Project = types.StringValue(project)
+	state.Location = types.StringValue(location)
+	state.DisplayName = types.StringPointerValue(repository.DisplayName)
+	state.CreateTime = types.StringPointerValue(repository.CreateTime)
+
+	var labels map[string]string
+	if repository.Labels != nil {
+		labels = *repository.Labels
+	}
+	state.Labels, diags = refreshLabels(ctx, state.Labels, labels)
+	resp.Diagnostics.Append(diags...)
+
+	// a missing file is committed again on the next apply
+	contents, err := client.ReadFile(state.Name.ValueString(), state.Path.ValueString())
+
+	switch {
+	case gcp.IsNotFound(err):
+		state.ContentSha256 = types.StringValue("")
+	case err != nil:
+		resp.Diagnostics.AddError(
+			"Error Reading GCP Notebook Content",
+			"Could not read "+state.Path.ValueString()+" from "+state.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	default:
+		state.ContentSha256 = types.StringValue(contentSha256(contents))
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update implements resource.Resource.
+func (n *notebookContentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	tflog.Debug(ctx, "********* In Update(notebook_content_resource) *********")
+
+	var plan, state notebookContentModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+	client := n.provider.dataformClient(project, location)
+
+	if !plan.DisplayName.Equal(state.DisplayName) || !plan.Labels.Equal(state.Labels) {
+
+		repository, diags := plan.toRepository(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		repository.Name = state.Name.ValueStringPointer()
+
+		if _, err := client.UpdateRepository(repository, "displayName,labels"); err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating notebook repository",
+				"Could not update Dataform repository, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if !plan.ContentSha256.Equal(state.ContentSha256) {
+
+		commitSha, err := n.commit(client, plan, plan.ContentSha256.ValueString())
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error committing notebook",
+				"Could not commit notebook content, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		plan.CommitSha = types.StringValue(commitSha)
+	}
+
+	// Set state to fully populated data
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete implements resource.Resource.
+func (n *notebookContentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(notebook_content_resource) *********")
+
+	var state notebookContentModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
+	err := n.provider.dataformClient(project, location).DeleteRepository(state.Name.ValueString())
+
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting notebook repository",
+			"Could not delete Dataform repository, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// commit writes the local file to the repository, returning the new commit
+// sha. expectedSha is the planned hash of the file.
+func (n *notebookContentResource) commit(client *gcp.DataformClient, plan notebookContentModel, expectedSha string) (string, error) {
+
+	contents, err := readNotebookSource(plan.Source.ValueString())
+	if err != nil {
+		return "", err
+	}
+
+	// the file may have changed since the plan was made
+	if sha := contentSha256(contents); sha != expectedSha {
+		return "", fmt.Errorf("%s changed after the plan was made (sha256 %s, planned %s)", plan.Source.ValueString(), sha, expectedSha)
+	}
+
+	message := "Update " + plan.Path.ValueString()
+	if !plan.CommitMessage.IsNull() {
+		message = plan.CommitMessage.ValueString()
+	}
+
+	author := gcp.CommitAuthor{
+		Name:         plan.AuthorName.ValueString(),
+		EmailAddress: plan.AuthorEmail.ValueString(),
+	}
+
+	return client.CommitFile(plan.Name.ValueString(), plan.Path.ValueString(), contents, author, message)
+}
+
+// toRepository converts the arguments into the API representation, always
+// carrying the label Colab Enterprise looks for
+func (m notebookContentModel) toRepository(ctx context.Context) (*gcp.DataformRepository, diag.Diagnostics) {
+
+	labels, diags := labelsFromValue(ctx, m.Labels)
+
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[gcp.ColabRepositoryLabel] = ""
+
+	return &gcp.DataformRepository{
+		DisplayName: m.DisplayName.ValueStringPointer(),
+		Labels:      &labels,
+	}, diags
+}
+
+// readNotebookSource reads the local notebook, making sure it is JSON
+func readNotebookSource(source string) ([]byte, error) {
+
+	contents, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("could not read notebook: %w", err)
+	}
+
+	if !json.Valid(contents) {
+		return nil, fmt.Errorf("%s is not a valid notebook, expected JSON", source)
+	}
+	return contents, nil
+}
+
+func contentSha256(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
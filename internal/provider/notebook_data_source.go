@@ -3,22 +3,34 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
 
 	"github.com/mpstella/terraform-provider-daw/internal/gcp"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ datasource.DataSource              = &notebookDataSource{}
-	_ datasource.DataSourceWithConfigure = &notebookDataSource{}
+	_ datasource.DataSource                   = &notebookDataSource{}
+	_ datasource.DataSourceWithConfigure      = &notebookDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &notebookDataSource{}
 )
 
+// fields the notebooks can be ordered by, mapped to the value used for comparison
+var notebookSortKeys = map[string]func(gcp.NotebookRuntimeTemplate) string{
+	"name":         func(t gcp.NotebookRuntimeTemplate) string { return stringOrEmpty(t.Name) },
+	"display_name": func(t gcp.NotebookRuntimeTemplate) string { return stringOrEmpty(t.DisplayName) },
+	"create_time":  func(t gcp.NotebookRuntimeTemplate) string { return stringOrEmpty(t.CreateTime) },
+	"update_time":  func(t gcp.NotebookRuntimeTemplate) string { return stringOrEmpty(t.UpdateTime) },
+}
+
 // just making alias to not get confused
 type notebookDataSource gcpNotebookClient
 
@@ -29,16 +41,58 @@ func (n *notebookDataSource) Configure(ctx context.Context, req datasource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*gcp.NotebookClient)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *gcp.NotebookClient, got %T", req.ProviderData),
+			fmt.Sprintf("Expected *providerData, got %T", req.ProviderData),
 		)
 		return
 	}
-	n.client = client
+	n.provider = data
+}
+
+// ValidateConfig implements datasource.DataSourceWithValidateConfig.
+func (n *notebookDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+
+	var data notebookDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.DisplayNameRegex.IsNull() && !data.DisplayNameRegex.IsUnknown() {
+		if _, err := regexp.Compile(data.DisplayNameRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("display_name_regex"),
+				"display_name_regex must be a valid regular expression",
+				"Could not compile display_name_regex: "+err.Error(),
+			)
+		}
+	}
+
+	if !data.SortBy.IsNull() && !data.SortBy.IsUnknown() {
+		if _, ok := notebookSortKeys[data.SortBy.ValueString()]; !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("sort_by"),
+				"sort_by must be one of name, display_name, create_time or update_time",
+				fmt.Sprintf("Expected sort_by to be a supported field, got: %s", data.SortBy.ValueString()),
+			)
+		}
+	}
+
+	if !data.SortOrder.IsNull() && !data.SortOrder.IsUnknown() {
+		if order := data.SortOrder.ValueString(); order != "asc" && order != "desc" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("sort_order"),
+				"sort_order must be either asc or desc",
+				fmt.Sprintf("Expected sort_order to be asc or desc, got: %s", order),
+			)
+		}
+	}
 }
 
 func NewNotebookDataSource() datasource.DataSource {
@@ -57,7 +111,17 @@ func (n *notebookDataSource) Read(ctx context.Context, req datasource.ReadReques
 
 	var state notebookDataSourceModel
 
-	notebooks, err := n.client.GetNotebooks()
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+	state.Project = types.StringValue(project)
+	state.Location = types.StringValue(location)
+
+	notebooks, err := n.provider.notebookClient(project, location).GetNotebooksWithFilter(state.Filter.ValueString())
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -67,78 +131,146 @@ func (n *notebookDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
+	labelSelector := make(map[string]string)
+	if !state.LabelSelector.IsNull() {
+		resp.Diagnostics.Append(state.LabelSelector.ElementsAs(ctx, &labelSelector, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var displayNameRegex *regexp.Regexp
+	if !state.DisplayNameRegex.IsNull() {
+		// only known values are checked by ValidateConfig
+		displayNameRegex, err = regexp.Compile(state.DisplayNameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("display_name_regex"),
+				"display_name_regex must be a valid regular expression",
+				"Could not compile display_name_regex: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	var templates []gcp.NotebookRuntimeTemplate
+
 	for _, notebook := range notebooks.NotebookRuntimeTemplates {
 
-		n, _ := notebook.AsString()
+		if !matchesLabelSelector(notebook.Labels, labelSelector) {
+			continue
+		}
 
-		tflog.Debug(ctx, "********* notebook *********", map[string]interface{}{"notebook": n})
+		if !state.MachineType.IsNull() && (notebook.MachineSpec == nil || stringOrEmpty(notebook.MachineSpec.MachineType) != state.MachineType.ValueString()) {
+			continue
+		}
 
-		notebookState := notebookModel{
-			Name:        types.StringPointerValue(notebook.Name),
-			DisplayName: types.StringPointerValue(notebook.DisplayName),
-			Description: types.StringPointerValue(notebook.Description),
-			IsDefault:   types.BoolPointerValue(notebook.IsDefault),
-			DataPersistentDiskSpec: notebookDataPersistentDiskSpecModel{
-				DiskType:   types.StringPointerValue(notebook.DataPersistentDiskSpec.DiskType),
-				DiskSizeGb: types.StringPointerValue(notebook.DataPersistentDiskSpec.DiskSizeGb),
-			},
+		if displayNameRegex != nil && !displayNameRegex.MatchString(stringOrEmpty(notebook.DisplayName)) {
+			continue
+		}
 
-			NetworkSpec: notebookNetworkSpecModel{
-				EnableInternetAccess: types.BoolPointerValue(notebook.NetworkSpec.EnableInternetAccess),
-				Network:              types.StringPointerValue(notebook.NetworkSpec.Network),
-				Subnetwork:           types.StringPointerValue(notebook.NetworkSpec.Subnetwork),
-			},
+		templates = append(templates, notebook)
+	}
 
-			IdleShutdownConfig: notebookIdleShutdownConfigModel{
-				IdleShutdownDisabled: types.BoolPointerValue(notebook.IdleShutdownConfig.IdleShutdownDisabled),
-				IdleTimeout:          types.StringPointerValue(notebook.IdleShutdownConfig.IdleTimeout),
-			},
+	// without sort_by the API order is kept
+	if !state.SortBy.IsNull() {
+		key := notebookSortKeys[state.SortBy.ValueString()]
+		descending := state.SortOrder.ValueString() == "desc"
+
+		sort.SliceStable(templates, func(i, j int) bool {
+			a, b := key(templates[i]), key(templates[j])
+			if a == b {
+				// fall back on the (unique) name so the order is deterministic
+				a, b = stringOrEmpty(templates[i].Name), stringOrEmpty(templates[j].Name)
+			}
+			if descending {
+				return a > b
+			}
+			return a < b
+		})
+	}
+
+	state.Notebooks = []notebookDataSourceItemModel{}
+
+	for _, notebook := range templates {
+
+		asString, _ := notebook.AsString()
+
+		tflog.Debug(ctx, "********* notebook *********", map[string]interface{}{"notebook": asString})
 
-			MachineSpec: notebookMachineSpecModel{
+		notebookState := notebookDataSourceItemModel{
+			Name:                types.StringPointerValue(notebook.Name),
+			DisplayName:         types.StringPointerValue(notebook.DisplayName),
+			Description:         types.StringPointerValue(notebook.Description),
+			IsDefault:           types.BoolValue(notebook.IsDefault != nil && *notebook.IsDefault),
+			EnableSecureBoot:    types.BoolValue(false),
+			KmsKeyName:          types.StringNull(),
+			ServiceAccount:      types.StringPointerValue(notebook.ServiceAccount),
+			Etag:                types.StringPointerValue(notebook.Etag),
+			CreateTime:          types.StringPointerValue(notebook.CreateTime),
+			UpdateTime:          types.StringPointerValue(notebook.UpdateTime),
+			NotebookRuntimeType: types.StringPointerValue(notebook.NotebookRuntimeType),
+			Labels:              types.MapNull(types.StringType),
+		}
+
+		if notebook.MachineSpec != nil {
+			notebookState.MachineSpec = &notebookMachineSpecModel{
 				MachineType:      types.StringPointerValue(notebook.MachineSpec.MachineType),
 				AcceleratorType:  types.StringPointerValue(notebook.MachineSpec.AcceleratorType),
 				AcceleratorCount: types.Int64PointerValue(notebook.MachineSpec.AcceleratorCount),
-			},
+			}
 		}
 
-		if notebook.ShieldedVmConfig != nil {
-			notebookState.EnableSecureBoot = types.BoolPointerValue(notebook.ShieldedVmConfig.EnableSecureBoot)
+		if notebook.DataPersistentDiskSpec != nil {
+			notebookState.DataPersistentDiskSpec = &notebookDataPersistentDiskSpecModel{
+				DiskType:   types.StringPointerValue(notebook.DataPersistentDiskSpec.DiskType),
+				DiskSizeGb: types.StringPointerValue(notebook.DataPersistentDiskSpec.DiskSizeGb),
+			}
 		}
 
-		// might be nil still, set to false
-		if notebookState.EnableSecureBoot.IsNull() {
-			notebookState.EnableSecureBoot = types.BoolValue(false)
+		if notebook.NetworkSpec != nil {
+			notebookState.NetworkSpec = &notebookNetworkSpecModel{
+				EnableInternetAccess: types.BoolValue(notebook.NetworkSpec.EnableInternetAccess != nil && *notebook.NetworkSpec.EnableInternetAccess),
+				Network:              types.StringPointerValue(notebook.NetworkSpec.Network),
+				Subnetwork:           types.StringPointerValue(notebook.NetworkSpec.Subnetwork),
+			}
 		}
 
-		if notebook.EncryptionSpec != nil {
-			notebookState.KmsKeyName = types.StringPointerValue(notebook.EncryptionSpec.KmsKeyName)
+		if notebook.IdleShutdownConfig != nil {
+			notebookState.IdleShutdownConfig = &notebookIdleShutdownConfigModel{
+				IdleTimeout:          types.StringPointerValue(notebook.IdleShutdownConfig.IdleTimeout),
+				IdleShutdownDisabled: types.BoolValue(notebook.IdleShutdownConfig.IdleShutdownDisabled != nil && *notebook.IdleShutdownConfig.IdleShutdownDisabled),
+			}
 		}
 
-		if notebook.NetworkSpec.EnableInternetAccess == nil {
-			notebookState.NetworkSpec.EnableInternetAccess = types.BoolValue(false)
+		if notebook.EucConfig != nil {
+			notebookState.EucConfig = &notebookEucConfigModel{
+				EucDisabled:      types.BoolValue(notebook.EucConfig.EucDisabled != nil && *notebook.EucConfig.EucDisabled),
+				BypassActasCheck: types.BoolValue(notebook.EucConfig.BypassActasCheck != nil && *notebook.EucConfig.BypassActasCheck),
+			}
 		}
 
-		if notebook.IsDefault == nil {
-			notebookState.IsDefault = types.BoolValue(false)
+		if notebook.ShieldedVmConfig != nil && notebook.ShieldedVmConfig.EnableSecureBoot != nil {
+			notebookState.EnableSecureBoot = types.BoolPointerValue(notebook.ShieldedVmConfig.EnableSecureBoot)
 		}
 
-		if notebook.IdleShutdownConfig.IdleShutdownDisabled == nil {
-			notebookState.IdleShutdownConfig.IdleShutdownDisabled = types.BoolValue(false)
+		if notebook.EncryptionSpec != nil {
+			notebookState.KmsKeyName = types.StringPointerValue(notebook.EncryptionSpec.KmsKeyName)
 		}
 
-		if notebook.Labels == nil {
-			notebookState.Labels = types.MapNull(types.StringType)
-		} else {
+		if notebook.Labels != nil {
 			var diags diag.Diagnostics
 			notebookState.Labels, diags = basetypes.NewMapValueFrom(ctx, types.StringType, *notebook.Labels)
 			resp.Diagnostics.Append(diags...)
 		}
 
+		notebookState.EstimatedHourlyCost, notebookState.WorstCaseDailyCost = n.provider.estimateCosts(ctx, location, notebookState.MachineSpec, notebookState.DataPersistentDiskSpec, notebookState.IdleShutdownConfig)
+
 		state.Notebooks = append(state.Notebooks, notebookState)
 	}
 
 	// Set state
-	diags := resp.State.Set(ctx, &state)
+	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -152,6 +284,41 @@ func (n *notebookDataSource) Schema(ctx context.Context, _ datasource.SchemaRequ
 
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The project to list templates in, defaults to the provider project",
+			},
+			"location": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The location to list templates in, defaults to the provider location",
+			},
+			"filter": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A server-side filter expression using the API filter syntax, e.g. `notebookRuntimeType=USER_DEFINED`",
+			},
+			"label_selector": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Only return templates carrying all of these label key/value pairs",
+			},
+			"machine_type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return templates using this machine type",
+			},
+			"display_name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return templates whose display name matches this regular expression",
+			},
+			"sort_by": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Sort the templates by `name`, `display_name`, `create_time` or `update_time`, the API order is kept when not set",
+			},
+			"sort_order": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Either `asc` (default) or `desc`",
+			},
 			"notebooks": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -172,24 +339,35 @@ func (n *notebookDataSource) Schema(ctx context.Context, _ datasource.SchemaRequ
 							Computed: true,
 						},
 						"kms_key_name": schema.StringAttribute{
-							Optional: true,
 							Computed: true,
 						},
+						"service_account": schema.StringAttribute{
+							Computed: true,
+						},
+						"etag": schema.StringAttribute{
+							Computed: true,
+						},
+						"create_time": schema.StringAttribute{
+							Computed: true,
+						},
+						"update_time": schema.StringAttribute{
+							Computed: true,
+						},
+						"notebook_runtime_type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Either `USER_DEFINED` or `ONE_CLICK`",
+						},
 						"machine_spec": schema.SingleNestedAttribute{
-							// Computed: true,
-							Optional: true,
+							Computed: true,
 							Attributes: map[string]schema.Attribute{
 								"machine_type": schema.StringAttribute{
-									// Computed: true,
-									Required: true,
+									Computed: true,
 								},
 								"accelerator_type": schema.StringAttribute{
-									Optional: true,
-									// Computed: true,
+									Computed: true,
 								},
 								"accelerator_count": schema.Int64Attribute{
-									Optional: true,
-									// Computed: true,
+									Computed: true,
 								},
 							},
 						},
@@ -205,13 +383,13 @@ func (n *notebookDataSource) Schema(ctx context.Context, _ datasource.SchemaRequ
 							},
 						},
 						"network_spec": schema.SingleNestedAttribute{
-							Required: true,
+							Computed: true,
 							Attributes: map[string]schema.Attribute{
 								"enable_internet_access": schema.BoolAttribute{
 									Computed: true,
 								},
 								"network": schema.StringAttribute{
-									Required: true,
+									Computed: true,
 								},
 								"subnetwork": schema.StringAttribute{
 									Computed: true,
@@ -219,24 +397,67 @@ func (n *notebookDataSource) Schema(ctx context.Context, _ datasource.SchemaRequ
 							},
 						},
 						"idle_shutdown_config": schema.SingleNestedAttribute{
-							Optional: true,
+							Computed: true,
 							Attributes: map[string]schema.Attribute{
 								"idle_timeout": schema.StringAttribute{
-									Optional: true,
+									Computed: true,
 								},
 								"idle_shutdown_disabled": schema.BoolAttribute{
-									Optional: true,
+									Computed: true,
+								},
+							},
+						},
+						"euc_config": schema.SingleNestedAttribute{
+							Computed: true,
+							Attributes: map[string]schema.Attribute{
+								"euc_disabled": schema.BoolAttribute{
+									Computed: true,
+								},
+								"bypass_actas_check": schema.BoolAttribute{
+									Computed: true,
 								},
 							},
 						},
 						"labels": schema.MapAttribute{
-							Description: "A set of key/value label pairs to assign to the resource.",
-							Optional:    true,
+							Description: "The key/value label pairs assigned to the template.",
+							Computed:    true,
 							ElementType: types.StringType,
 						},
+						"estimated_hourly_cost": schema.Float64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The estimated on-demand cost of an hour of runtime, null when the price catalogue doesn't cover the template",
+						},
+						"worst_case_daily_cost": schema.Float64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The estimated cost of a runtime left idle for a day, until its idle shutdown",
+						},
 					},
 				},
 			},
 		},
 	}
 }
+
+// matchesLabelSelector reports whether every selector key/value pair is present in labels
+func matchesLabelSelector(labels *map[string]string, selector map[string]string) bool {
+
+	if len(selector) == 0 {
+		return true
+	}
+	if labels == nil {
+		return false
+	}
+	for k, v := range selector {
+		if value, ok := (*labels)[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,372 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &notebookDefaultResource{}
+	_ resource.ResourceWithConfigure      = &notebookDefaultResource{}
+	_ resource.ResourceWithValidateConfig = &notebookDefaultResource{}
+	_ resource.ResourceWithModifyPlan     = &notebookDefaultResource{}
+	_ resource.ResourceWithImportState    = &notebookDefaultResource{}
+)
+
+// just making alias to not get confused
+type notebookDefaultResource gcpNotebookClient
+
+type notebookDefaultModel struct {
+	Id                      types.String `tfsdk:"id"`
+	Project                 types.String `tfsdk:"project"`
+	Location                types.String `tfsdk:"location"`
+	NotebookRuntimeTemplate types.String `tfsdk:"notebook_runtime_template"`
+}
+
+// matches the id of the resource, the location whose default it manages
+var locationNameRegexp = regexp.MustCompile(`^projects/([^/]+)/locations/([^/]+)$`)
+
+func NewNotebookDefaultResource() resource.Resource {
+	return &notebookDefaultResource{}
+}
+
+func (n *notebookDefaultResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	n.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (n *notebookDefaultResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_default"
+}
+
+// Schema implements resource.Resource.
+func (n *notebookDefaultResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(notebook_default_resource) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Makes a runtime template the default of its location, the flag is cleared on the template that was the default before. " +
+			"Use this rather than `is_default` on `daw_notebook`, a location only has one default.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The location, `projects/{project}/locations/{location}`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The project of the template, defaults to the project in its name or the provider project",
+			},
+			"location": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The location of the template, defaults to the location in its name or the provider location",
+			},
+			"notebook_runtime_template": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the template to make the default, e.g. the `name` of a `daw_notebook`",
+			},
+		},
+	}
+}
+
+func (n *notebookDefaultResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+
+	var data notebookDefaultModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.NotebookRuntimeTemplate.IsNull() || data.NotebookRuntimeTemplate.IsUnknown() {
+		return
+	}
+
+	project, location, _, err := gcp.ParseTemplateName(data.NotebookRuntimeTemplate.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("notebook_runtime_template"), "Invalid template name", err.Error())
+		return
+	}
+
+	if !data.Project.IsNull() && !data.Project.IsUnknown() && data.Project.ValueString() != project {
+		resp.Diagnostics.AddAttributeError(path.Root("project"), "Template in another project",
+			fmt.Sprintf("The template is in project %s, not %s", project, data.Project.ValueString()))
+	}
+	if !data.Location.IsNull() && !data.Location.IsUnknown() && data.Location.ValueString() != location {
+		resp.Diagnostics.AddAttributeError(path.Root("location"), "Template in another location",
+			fmt.Sprintf("The template is in location %s, not %s", location, data.Location.ValueString()))
+	}
+}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan, it resolves the
+// location and claims its default so a second claim in the configuration
+// fails the plan.
+func (n *notebookDefaultResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	tflog.Debug(ctx, "********* In ModifyPlan(notebook_default_resource) *********")
+
+	// nothing to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() || n.provider == nil {
+		return
+	}
+
+	var plan notebookDefaultModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the template name is the better guess, the provider values only apply
+	// while it isn't known
+	project, location := plan.Project, plan.Location
+	if !plan.NotebookRuntimeTemplate.IsUnknown() {
+		if p, l, _, err := gcp.ParseTemplateName(plan.NotebookRuntimeTemplate.ValueString()); err == nil {
+			if project.IsNull() || project.IsUnknown() {
+				project = types.StringValue(p)
+			}
+			if location.IsNull() || location.IsUnknown() {
+				location = types.StringValue(l)
+			}
+		}
+	}
+
+	p, l := n.provider.projectLocation(project, location)
+
+	plan.Project = types.StringValue(p)
+	plan.Location = types.StringValue(l)
+	plan.Id = types.StringValue(fmt.Sprintf("projects/%s/locations/%s", p, l))
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+
+	if !req.State.Raw.IsNull() {
+		var state notebookDefaultModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if !plan.Id.Equal(state.Id) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("project"), path.Root("location"))
+		}
+	}
+
+	claimant := n.provider.defaultTemplates.claimant("daw_notebook_default", plan.NotebookRuntimeTemplate)
+
+	if err := n.provider.defaultTemplates.claim(p, l, claimant); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("notebook_runtime_template"), "Second default template", err.Error())
+	}
+}
+
+// Create implements resource.Resource.
+func (n *notebookDefaultResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(notebook_default_resource) *********")
+
+	var plan notebookDefaultModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := n.makeDefault(ctx, plan); err != nil {
+		resp.Diagnostics.AddError(
+			"Error setting default template",
+			"Could not make "+plan.NotebookRuntimeTemplate.ValueString()+" the default, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read implements resource.Resource.
+func (n *notebookDefaultResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(notebook_default_resource) *********")
+
+	var state notebookDefaultModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	notebooks, err := n.provider.notebookClient(state.Project.ValueString(), state.Location.ValueString()).GetNotebooks()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading default template",
+			"Could not list templates, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	var current string
+	for _, notebook := range notebooks.NotebookRuntimeTemplates {
+		if notebook.IsDefault != nil && *notebook.IsDefault && notebook.Name != nil {
+			current = *notebook.Name
+			break
+		}
+	}
+
+	// imported by location, the template is whichever is the default
+	if state.NotebookRuntimeTemplate.IsNull() && current != "" {
+		state.NotebookRuntimeTemplate = types.StringValue(current)
+	}
+
+	// another (or no) template became the default, planning makes it ours again
+	if current == "" || current != state.NotebookRuntimeTemplate.ValueString() {
+		tflog.Debug(ctx, "Template is no longer the default", map[string]interface{}{"template": state.NotebookRuntimeTemplate.ValueString(), "default": current})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource, only the template can change in place.
+func (n *notebookDefaultResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	tflog.Debug(ctx, "********* In Update(notebook_default_resource) *********")
+
+	var plan notebookDefaultModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := n.makeDefault(ctx, plan); err != nil {
+		resp.Diagnostics.AddError(
+			"Error setting default template",
+			"Could not make "+plan.NotebookRuntimeTemplate.ValueString()+" the default, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete implements resource.Resource, the template stays but no longer is the default.
+func (n *notebookDefaultResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(notebook_default_resource) *********")
+
+	var state notebookDefaultModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := state.Project.ValueString(), state.Location.ValueString()
+
+	n.provider.locks.Lock(defaultTemplateLockKey(project, location))
+	defer n.provider.locks.Unlock(defaultTemplateLockKey(project, location))
+
+	err := n.provider.notebookClient(project, location).SetDefault(state.NotebookRuntimeTemplate.ValueString(), false)
+
+	// the template going away takes the flag with it
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting default template",
+			"Could not clear the default flag, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState, the id is the
+// location (projects/{project}/locations/{location}) or the template name.
+func (n *notebookDefaultResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	project, location, _, err := gcp.ParseTemplateName(req.ID)
+
+	if err == nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("notebook_runtime_template"), req.ID)...)
+	} else if matches := locationNameRegexp.FindStringSubmatch(req.ID); matches != nil {
+		project, location = matches[1], matches[2]
+	} else {
+		resp.Diagnostics.AddError("Invalid import id", "expected projects/{project}/locations/{location} or a template name, got: "+req.ID)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("projects/%s/locations/%s", project, location))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project"), project)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("location"), location)...)
+}
+
+// makeDefault clears the flag on every other template of the location and
+// sets it on the planned one, holding the lock of the location throughout
+func (n *notebookDefaultResource) makeDefault(ctx context.Context, plan notebookDefaultModel) error {
+
+	project, location := plan.Project.ValueString(), plan.Location.ValueString()
+	template := plan.NotebookRuntimeTemplate.ValueString()
+
+	if p, l, _, err := gcp.ParseTemplateName(template); err != nil {
+		return err
+	} else if p != project || l != location {
+		return fmt.Errorf("the template is in %s/%s, not %s/%s", p, l, project, location)
+	}
+
+	n.provider.locks.Lock(defaultTemplateLockKey(project, location))
+	defer n.provider.locks.Unlock(defaultTemplateLockKey(project, location))
+
+	client := n.provider.notebookClient(project, location)
+
+	notebooks, err := client.GetNotebooks()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, notebook := range notebooks.NotebookRuntimeTemplates {
+
+		if notebook.Name == nil {
+			continue
+		}
+		if *notebook.Name == template {
+			found = true
+			continue
+		}
+
+		if notebook.IsDefault != nil && *notebook.IsDefault {
+			tflog.Info(ctx, "Clearing the previous default template", map[string]interface{}{"template": *notebook.Name})
+
+			if err := client.SetDefault(*notebook.Name, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("could not find template %s", template)
+	}
+	return client.SetDefault(template, true)
+}
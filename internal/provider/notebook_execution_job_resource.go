@@ -0,0 +1,515 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &notebookExecutionJobResource{}
+	_ resource.ResourceWithConfigure      = &notebookExecutionJobResource{}
+	_ resource.ResourceWithValidateConfig = &notebookExecutionJobResource{}
+)
+
+// how long to wait for a job without an execution_timeout, the API default
+const defaultExecutionTimeout = 24 * time.Hour
+
+// just making alias to not get confused
+type notebookExecutionJobResource gcpNotebookClient
+
+func NewNotebookExecutionJobResource() resource.Resource {
+	return &notebookExecutionJobResource{}
+}
+
+func (n *notebookExecutionJobResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	n.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (n *notebookExecutionJobResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_execution_job"
+}
+
+func (n *notebookExecutionJobResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+
+	var data notebookExecutionJobModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateExecutionJobSpec(tfpath.Empty(), data.spec())...)
+}
+
+// Schema implements resource.Resource.
+func (n *notebookExecutionJobResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(notebook_execution_job_resource) *********")
+
+	attributes := executionJobSpecAttributes(true)
+
+	attributes["name"] = schema.StringAttribute{
+		Computed: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+	attributes["project"] = schema.StringAttribute{
+		Optional: true,
+		Computed: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+			stringplanmodifier.RequiresReplaceIfConfigured(),
+		},
+		MarkdownDescription: "The project to run the job in, defaults to the provider project",
+	}
+	attributes["location"] = schema.StringAttribute{
+		Optional: true,
+		Computed: true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+			stringplanmodifier.RequiresReplaceIfConfigured(),
+		},
+		MarkdownDescription: "The location to run the job in, defaults to the provider location",
+	}
+	attributes["job_state"] = schema.StringAttribute{
+		Computed:            true,
+		MarkdownDescription: "The final state of the job, e.g. `JOB_STATE_SUCCEEDED`",
+	}
+	attributes["status_message"] = schema.StringAttribute{
+		Computed:            true,
+		MarkdownDescription: "The error message of a failed job",
+	}
+	attributes["output_uri"] = schema.StringAttribute{
+		Computed:            true,
+		MarkdownDescription: "The Cloud Storage folder the executed notebook is written to",
+	}
+	attributes["create_time"] = schema.StringAttribute{
+		Computed: true,
+	}
+	attributes["update_time"] = schema.StringAttribute{
+		Computed: true,
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs a notebook once on a runtime template and waits for it to finish. Changing any argument runs the notebook again.",
+		Attributes:          attributes,
+	}
+}
+
+// Create implements resource.Resource.
+func (n *notebookExecutionJobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(notebook_execution_job_resource) *********")
+
+	var plan notebookExecutionJobModel
+	diags := req.Plan.Get(ctx, &plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	job, diags := plan.spec().toExecutionJob(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+	plan.Project = types.StringValue(project)
+	plan.Location = types.StringValue(location)
+
+	client := n.provider.notebookClient(project, location)
+
+	created, err := client.CreateNotebookExecutionJob(ctx, job)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating execution job",
+			"Could not create execution job, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// save what we have so the job is tracked even if waiting fails
+	plan.Name = types.StringPointerValue(created.Name)
+	plan.refresh(created)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := defaultExecutionTimeout
+	if !plan.ExecutionTimeout.IsNull() {
+		timeout, _ = parseDurationSeconds(plan.ExecutionTimeout.ValueString())
+	}
+
+	// give the job some slack to be scheduled and written out
+	finished, err := client.WaitForNotebookExecutionJob(ctx, plan.Name.ValueString(), timeout+15*time.Minute)
+
+	if finished != nil {
+		plan.refresh(finished)
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error waiting for execution job",
+			"Could not wait for the execution job to finish, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// the failed job stays in state (tainted) so the next apply runs it again
+	if plan.JobState.ValueString() != gcp.JobStateSucceeded {
+		resp.Diagnostics.AddError(
+			"Execution job did not succeed",
+			fmt.Sprintf("The execution job %s finished in state %s: %s", plan.Name.ValueString(), plan.JobState.ValueString(), plan.StatusMessage.ValueString()),
+		)
+	}
+}
+
+// Read implements resource.Resource.
+func (n *notebookExecutionJobResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(notebook_execution_job_resource) *********")
+
+	var state notebookExecutionJobModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
+	job, err := n.provider.notebookClient(project, location).GetNotebookExecutionJob(state.Name.ValueString())
+
+	if gcp.IsNotFound(err) {
+		tflog.Warn(ctx, "Execution job no longer exists, removing from state", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading GCP Execution Job",
+			"Could not read execution job with name "+state.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Project = types.StringValue(project)
+	state.Location = types.StringValue(location)
+	state.refresh(job)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update implements resource.Resource, every argument forces a new run.
+func (n *notebookExecutionJobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan notebookExecutionJobModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete implements resource.Resource.
+func (n *notebookExecutionJobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(notebook_execution_job_resource) *********")
+
+	var state notebookExecutionJobModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
+	err := n.provider.notebookClient(project, location).DeleteNotebookExecutionJob(ctx, state.Name.ValueString())
+
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting execution job",
+			"Could not delete execution job, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// spec returns the arguments of the job
+func (m notebookExecutionJobModel) spec() notebookExecutionJobSpecModel {
+	return notebookExecutionJobSpecModel{
+		DisplayName:              m.DisplayName,
+		NotebookRuntimeTemplate:  m.NotebookRuntimeTemplate,
+		GcsNotebookSource:        m.GcsNotebookSource,
+		DataformRepositorySource: m.DataformRepositorySource,
+		GcsOutputUri:             m.GcsOutputUri,
+		ExecutionUser:            m.ExecutionUser,
+		ServiceAccount:           m.ServiceAccount,
+		ExecutionTimeout:         m.ExecutionTimeout,
+		Labels:                   m.Labels,
+	}
+}
+
+// refresh copies the server-side state of the job into the model
+func (m *notebookExecutionJobModel) refresh(job *gcp.NotebookExecutionJob) {
+
+	m.JobState = types.StringPointerValue(job.JobState)
+	m.StatusMessage = types.StringNull()
+	m.CreateTime = types.StringPointerValue(job.CreateTime)
+	m.UpdateTime = types.StringPointerValue(job.UpdateTime)
+
+	if job.Status != nil && job.Status.Message != "" {
+		m.StatusMessage = types.StringValue(job.Status.Message)
+	}
+
+	// the executed notebook is written to a folder named after the job
+	m.OutputUri = types.StringNull()
+	if job.GcsOutputUri != nil && job.Name != nil {
+		m.OutputUri = types.StringValue(strings.TrimSuffix(*job.GcsOutputUri, "/") + "/" + path.Base(*job.Name))
+	}
+}
+
+// executionJobSpecAttributes are the arguments of a job, shared with the
+// schedule resource. Jobs are immutable, so the job resource replaces on any change.
+func executionJobSpecAttributes(replace bool) map[string]schema.Attribute {
+
+	var stringModifiers []planmodifier.String
+	var objectModifiers []planmodifier.Object
+	var mapModifiers []planmodifier.Map
+
+	if replace {
+		stringModifiers = []planmodifier.String{stringplanmodifier.RequiresReplace()}
+		objectModifiers = []planmodifier.Object{objectplanmodifier.RequiresReplace()}
+		mapModifiers = []planmodifier.Map{mapplanmodifier.RequiresReplace()}
+	}
+
+	return map[string]schema.Attribute{
+		"display_name": schema.StringAttribute{
+			Required:      true,
+			PlanModifiers: stringModifiers,
+		},
+		"notebook_runtime_template": schema.StringAttribute{
+			Required:            true,
+			PlanModifiers:       stringModifiers,
+			MarkdownDescription: "The full name of the runtime template to run on, e.g. `daw_notebook.example.name`",
+		},
+		"gcs_notebook_source": schema.SingleNestedAttribute{
+			Optional:            true,
+			PlanModifiers:       objectModifiers,
+			MarkdownDescription: "Run a notebook stored in Cloud Storage, conflicts with `dataform_repository_source`",
+			Attributes: map[string]schema.Attribute{
+				"uri": schema.StringAttribute{
+					Required:            true,
+					MarkdownDescription: "The Cloud Storage uri of the notebook, e.g. `gs://bucket/notebook.ipynb`",
+				},
+				"generation": schema.StringAttribute{
+					Optional:            true,
+					MarkdownDescription: "The object generation to run, the latest when not set",
+				},
+			},
+		},
+		"dataform_repository_source": schema.SingleNestedAttribute{
+			Optional:            true,
+			PlanModifiers:       objectModifiers,
+			MarkdownDescription: "Run a notebook stored in a Dataform repository, conflicts with `gcs_notebook_source`",
+			Attributes: map[string]schema.Attribute{
+				"dataform_repository_resource_name": schema.StringAttribute{
+					Required:            true,
+					MarkdownDescription: "The full name of the Dataform repository",
+				},
+				"commit_sha": schema.StringAttribute{
+					Optional:            true,
+					MarkdownDescription: "The commit to run, the latest when not set",
+				},
+			},
+		},
+		"gcs_output_uri": schema.StringAttribute{
+			Required:            true,
+			PlanModifiers:       stringModifiers,
+			MarkdownDescription: "The Cloud Storage location the executed notebook is written to, e.g. `gs://bucket/output`",
+		},
+		"execution_user": schema.StringAttribute{
+			Optional:            true,
+			PlanModifiers:       stringModifiers,
+			MarkdownDescription: "The user the notebook runs as, conflicts with `service_account`",
+		},
+		"service_account": schema.StringAttribute{
+			Optional:            true,
+			PlanModifiers:       stringModifiers,
+			MarkdownDescription: "The service account the notebook runs as, conflicts with `execution_user`",
+		},
+		"execution_timeout": schema.StringAttribute{
+			Optional:            true,
+			PlanModifiers:       stringModifiers,
+			MarkdownDescription: "The maximum run time in seconds, e.g. `3600s`. Defaults to 24 hours",
+		},
+		"labels": schema.MapAttribute{
+			Optional:      true,
+			ElementType:   types.StringType,
+			PlanModifiers: mapModifiers,
+		},
+	}
+}
+
+// validateExecutionJobSpec checks the arguments of a job, at is where the
+// arguments live in the schema
+func validateExecutionJobSpec(at tfpath.Path, spec notebookExecutionJobSpecModel) diag.Diagnostics {
+
+	var diags diag.Diagnostics
+
+	if spec.GcsNotebookSource == nil && spec.DataformRepositorySource == nil {
+		diags.AddAttributeError(
+			at.AtName("gcs_notebook_source"),
+			"A notebook source is required",
+			"Expected one of gcs_notebook_source or dataform_repository_source to be configured",
+		)
+	}
+
+	if spec.GcsNotebookSource != nil && spec.DataformRepositorySource != nil {
+		diags.AddAttributeError(
+			at.AtName("dataform_repository_source"),
+			"gcs_notebook_source and dataform_repository_source conflict",
+			"Expected only one of gcs_notebook_source or dataform_repository_source to be configured",
+		)
+	}
+
+	if !spec.ExecutionUser.IsNull() && !spec.ServiceAccount.IsNull() {
+		diags.AddAttributeError(
+			at.AtName("service_account"),
+			"execution_user and service_account conflict",
+			"Expected only one of execution_user or service_account to be configured",
+		)
+	}
+
+	if !spec.ExecutionTimeout.IsNull() && !spec.ExecutionTimeout.IsUnknown() {
+		if _, err := parseDurationSeconds(spec.ExecutionTimeout.ValueString()); err != nil {
+			diags.AddAttributeError(
+				at.AtName("execution_timeout"),
+				"execution_timeout must end in 's' and be a valid integer",
+				"Expected execution_timeout to be a number of seconds, e.g. 3600s: "+err.Error(),
+			)
+		}
+	}
+
+	return diags
+}
+
+// toExecutionJob converts the arguments into the API representation
+func (m notebookExecutionJobSpecModel) toExecutionJob(ctx context.Context) (*gcp.NotebookExecutionJob, diag.Diagnostics) {
+
+	job := &gcp.NotebookExecutionJob{
+		DisplayName:                         m.DisplayName.ValueStringPointer(),
+		NotebookRuntimeTemplateResourceName: m.NotebookRuntimeTemplate.ValueStringPointer(),
+		GcsOutputUri:                        m.GcsOutputUri.ValueStringPointer(),
+		ExecutionUser:                       m.ExecutionUser.ValueStringPointer(),
+		ServiceAccount:                      m.ServiceAccount.ValueStringPointer(),
+		ExecutionTimeout:                    m.ExecutionTimeout.ValueStringPointer(),
+	}
+
+	if m.GcsNotebookSource != nil {
+		job.GcsNotebookSource = &gcp.GcsNotebookSource{
+			Uri:        m.GcsNotebookSource.Uri.ValueStringPointer(),
+			Generation: m.GcsNotebookSource.Generation.ValueStringPointer(),
+		}
+	}
+
+	if m.DataformRepositorySource != nil {
+		job.DataformRepositorySource = &gcp.DataformRepositorySource{
+			DataformRepositoryResourceName: m.DataformRepositorySource.DataformRepositoryResourceName.ValueStringPointer(),
+			CommitSha:                      m.DataformRepositorySource.CommitSha.ValueStringPointer(),
+		}
+	}
+
+	labels, diags := labelsFromValue(ctx, m.Labels)
+	if len(labels) > 0 {
+		job.Labels = &labels
+	}
+
+	return job, diags
+}
+
+// executionJobSpecFromAPI converts a job back into its arguments
+func executionJobSpecFromAPI(ctx context.Context, job *gcp.NotebookExecutionJob) (notebookExecutionJobSpecModel, diag.Diagnostics) {
+
+	spec := notebookExecutionJobSpecModel{
+		DisplayName:             types.StringPointerValue(job.DisplayName),
+		NotebookRuntimeTemplate: types.StringPointerValue(job.NotebookRuntimeTemplateResourceName),
+		GcsOutputUri:            types.StringPointerValue(job.GcsOutputUri),
+		ExecutionUser:           types.StringPointerValue(job.ExecutionUser),
+		ServiceAccount:          types.StringPointerValue(job.ServiceAccount),
+		ExecutionTimeout:        types.StringPointerValue(job.ExecutionTimeout),
+	}
+
+	if job.GcsNotebookSource != nil {
+		spec.GcsNotebookSource = &notebookGcsNotebookSourceModel{
+			Uri:        types.StringPointerValue(job.GcsNotebookSource.Uri),
+			Generation: types.StringPointerValue(job.GcsNotebookSource.Generation),
+		}
+	}
+
+	if job.DataformRepositorySource != nil {
+		spec.DataformRepositorySource = &notebookDataformRepositorySourceModel{
+			DataformRepositoryResourceName: types.StringPointerValue(job.DataformRepositorySource.DataformRepositoryResourceName),
+			CommitSha:                      types.StringPointerValue(job.DataformRepositorySource.CommitSha),
+		}
+	}
+
+	var labels map[string]string
+	if job.Labels != nil {
+		labels = *job.Labels
+	}
+
+	var diags diag.Diagnostics
+	spec.Labels, diags = labelsMapValue(ctx, labels)
+
+	return spec, diags
+}
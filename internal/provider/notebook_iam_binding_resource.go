@@ -0,0 +1,289 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &notebookIamBindingResource{}
+	_ resource.ResourceWithConfigure      = &notebookIamBindingResource{}
+	_ resource.ResourceWithValidateConfig = &notebookIamBindingResource{}
+	_ resource.ResourceWithImportState    = &notebookIamBindingResource{}
+)
+
+// just making alias to not get confused
+type notebookIamBindingResource gcpNotebookClient
+
+func NewNotebookIamBindingResource() resource.Resource {
+	return &notebookIamBindingResource{}
+}
+
+func (n *notebookIamBindingResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	n.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (n *notebookIamBindingResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_iam_binding"
+}
+
+func (n *notebookIamBindingResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+
+	var data notebookIamBindingModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.NotebookRuntimeTemplate.IsNull() && !data.NotebookRuntimeTemplate.IsUnknown() {
+		if _, _, _, err := gcp.ParseTemplateName(data.NotebookRuntimeTemplate.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("notebook_runtime_template"), "Invalid template name", err.Error())
+		}
+	}
+}
+
+// Schema implements resource.Resource.
+func (n *notebookIamBindingResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(notebook_iam_binding_resource) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Authoritatively sets the members of a role on a runtime template, other roles are left alone",
+		Attributes: map[string]schema.Attribute{
+			"notebook_runtime_template": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The full name of the runtime template, e.g. `daw_notebook.example.name`",
+			},
+			"role": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The role to grant, e.g. `roles/aiplatform.notebookRuntimeUser`",
+			},
+			"members": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The members granted the role, e.g. `user:jane@example.com` or `group:analysts@example.com`",
+			},
+			"condition": iamConditionSchema(),
+			"etag": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Create implements resource.Resource.
+func (n *notebookIamBindingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(notebook_iam_binding_resource) *********")
+
+	var plan notebookIamBindingModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	n.setBinding(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read implements resource.Resource.
+func (n *notebookIamBindingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(notebook_iam_binding_resource) *********")
+
+	var state notebookIamBindingModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template := state.NotebookRuntimeTemplate.ValueString()
+
+	client, err := n.provider.templateClient(template)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid template name", err.Error())
+		return
+	}
+
+	policy, err := client.GetIamPolicy(template)
+
+	if gcp.IsNotFound(err) {
+		tflog.Warn(ctx, "Template no longer exists, removing binding from state", map[string]interface{}{"name": template})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading IAM policy",
+			"Could not read the IAM policy of "+template+": "+err.Error(),
+		)
+		return
+	}
+
+	i := findIamBinding(policy, state.Role.ValueString(), state.Condition)
+
+	if i < 0 {
+		tflog.Warn(ctx, "Binding no longer exists, removing from state", map[string]interface{}{"role": state.Role.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	var diags diag.Diagnostics
+	state.Members, diags = types.SetValueFrom(ctx, types.StringType, policy.Bindings[i].Members)
+	resp.Diagnostics.Append(diags...)
+	state.Condition = iamConditionModel(policy.Bindings[i].Condition)
+	state.Etag = types.StringValue(policy.Etag)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource.
+func (n *notebookIamBindingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	tflog.Debug(ctx, "********* In Update(notebook_iam_binding_resource) *********")
+
+	var plan notebookIamBindingModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	n.setBinding(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete implements resource.Resource.
+func (n *notebookIamBindingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(notebook_iam_binding_resource) *********")
+
+	var state notebookIamBindingModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := n.provider.modifyTemplatePolicy(state.NotebookRuntimeTemplate.ValueString(), func(policy *gcp.Policy) error {
+		if i := findIamBinding(policy, state.Role.ValueString(), state.Condition); i >= 0 {
+			policy.Bindings = append(policy.Bindings[:i], policy.Bindings[i+1:]...)
+		}
+		return nil
+	})
+
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting IAM binding",
+			"Could not remove the IAM binding, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState, the id is
+// "{template} {role}" optionally followed by the condition title.
+func (n *notebookIamBindingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	parts, conditionTitle, err := splitIamImportID(req.ID, 2)
+	if err == nil {
+		_, _, _, err = gcp.ParseTemplateName(parts[0])
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import id", "Expected \"{template} {role} [condition title]\": "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("notebook_runtime_template"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role"), parts[1])...)
+
+	if conditionTitle != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("condition"), &notebookIamConditionModel{
+			Title:       types.StringValue(conditionTitle),
+			Description: types.StringNull(),
+			Expression:  types.StringNull(),
+		})...)
+	}
+}
+
+// setBinding replaces the members of the role (and condition) with the plan
+func (n *notebookIamBindingResource) setBinding(ctx context.Context, plan *notebookIamBindingModel, diags *diag.Diagnostics) {
+
+	var members []string
+	diags.Append(plan.Members.ElementsAs(ctx, &members, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	policy, err := n.provider.modifyTemplatePolicy(plan.NotebookRuntimeTemplate.ValueString(), func(policy *gcp.Policy) error {
+
+		binding := gcp.Binding{
+			Role:      plan.Role.ValueString(),
+			Members:   members,
+			Condition: iamConditionExpr(plan.Condition),
+		}
+
+		if i := findIamBinding(policy, plan.Role.ValueString(), plan.Condition); i >= 0 {
+			policy.Bindings[i] = binding
+		} else {
+			policy.Bindings = append(policy.Bindings, binding)
+		}
+
+		removeEmptyIamBindings(policy)
+		return nil
+	})
+
+	if err != nil {
+		diags.AddError(
+			"Error setting IAM binding",
+			"Could not set the IAM binding, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.Etag = types.StringValue(policy.Etag)
+}
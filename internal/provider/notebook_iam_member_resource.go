@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &notebookIamMemberResource{}
+	_ resource.ResourceWithConfigure      = &notebookIamMemberResource{}
+	_ resource.ResourceWithValidateConfig = &notebookIamMemberResource{}
+	_ resource.ResourceWithImportState    = &notebookIamMemberResource{}
+)
+
+// just making alias to not get confused
+type notebookIamMemberResource gcpNotebookClient
+
+func NewNotebookIamMemberResource() resource.Resource {
+	return &notebookIamMemberResource{}
+}
+
+func (n *notebookIamMemberResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	n.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (n *notebookIamMemberResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_iam_member"
+}
+
+func (n *notebookIamMemberResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+
+	var data notebookIamMemberModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.NotebookRuntimeTemplate.IsNull() && !data.NotebookRuntimeTemplate.IsUnknown() {
+		if _, _, _, err := gcp.ParseTemplateName(data.NotebookRuntimeTemplate.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("notebook_runtime_template"), "Invalid template name", err.Error())
+		}
+	}
+}
+
+// Schema implements resource.Resource.
+func (n *notebookIamMemberResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(notebook_iam_member_resource) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Grants a role on a runtime template to a single member, other members of the role are left alone",
+		Attributes: map[string]schema.Attribute{
+			"notebook_runtime_template": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The full name of the runtime template, e.g. `daw_notebook.example.name`",
+			},
+			"role": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The role to grant, e.g. `roles/aiplatform.notebookRuntimeUser`",
+			},
+			"member": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The member granted the role, e.g. `user:jane@example.com`",
+			},
+			"condition": iamConditionSchema(),
+			"etag": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Create implements resource.Resource.
+func (n *notebookIamMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(notebook_iam_member_resource) *********")
+
+	var plan notebookIamMemberModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policy, err := n.provider.modifyTemplatePolicy(plan.NotebookRuntimeTemplate.ValueString(), func(policy *gcp.Policy) error {
+
+		i := findIamBinding(policy, plan.Role.ValueString(), plan.Condition)
+
+		if i < 0 {
+			policy.Bindings = append(policy.Bindings, gcp.Binding{
+				Role:      plan.Role.ValueString(),
+				Condition: iamConditionExpr(plan.Condition),
+			})
+			i = len(policy.Bindings) - 1
+		}
+
+		if memberIndex(policy.Bindings[i].Members, plan.Member.ValueString()) < 0 {
+			policy.Bindings[i].Members = append(policy.Bindings[i].Members, plan.Member.ValueString())
+		}
+		return nil
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error adding IAM member",
+			"Could not add the IAM member, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.Etag = types.StringValue(policy.Etag)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read implements resource.Resource.
+func (n *notebookIamMemberResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(notebook_iam_member_resource) *********")
+
+	var state notebookIamMemberModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template := state.NotebookRuntimeTemplate.ValueString()
+
+	client, err := n.provider.templateClient(template)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid template name", err.Error())
+		return
+	}
+
+	policy, err := client.GetIamPolicy(template)
+
+	if gcp.IsNotFound(err) {
+		tflog.Warn(ctx, "Template no longer exists, removing member from state", map[string]interface{}{"name": template})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading IAM policy",
+			"Could not read the IAM policy of "+template+": "+err.Error(),
+		)
+		return
+	}
+
+	i := findIamBinding(policy, state.Role.ValueString(), state.Condition)
+
+	if i < 0 || memberIndex(policy.Bindings[i].Members, state.Member.ValueString()) < 0 {
+		tflog.Warn(ctx, "Member no longer has the role, removing from state", map[string]interface{}{"role": state.Role.ValueString(), "member": state.Member.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Condition = iamConditionModel(policy.Bindings[i].Condition)
+	state.Etag = types.StringValue(policy.Etag)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource, every attribute forces replacement.
+func (n *notebookIamMemberResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	var plan notebookIamMemberModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete implements resource.Resource.
+func (n *notebookIamMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(notebook_iam_member_resource) *********")
+
+	var state notebookIamMemberModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := n.provider.modifyTemplatePolicy(state.NotebookRuntimeTemplate.ValueString(), func(policy *gcp.Policy) error {
+
+		if i := findIamBinding(policy, state.Role.ValueString(), state.Condition); i >= 0 {
+			members := policy.Bindings[i].Members
+			if j := memberIndex(members, state.Member.ValueString()); j >= 0 {
+				policy.Bindings[i].Members = append(members[:j], members[j+1:]...)
+			}
+		}
+
+		removeEmptyIamBindings(policy)
+		return nil
+	})
+
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error removing IAM member",
+			"Could not remove the IAM member, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState, the id is
+// "{template} {role} {member}" optionally followed by the condition title.
+func (n *notebookIamMemberResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	parts, conditionTitle, err := splitIamImportID(req.ID, 3)
+	if err == nil {
+		_, _, _, err = gcp.ParseTemplateName(parts[0])
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import id", "Expected \"{template} {role} {member} [condition title]\": "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("notebook_runtime_template"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("member"), parts[2])...)
+
+	if conditionTitle != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("condition"), &notebookIamConditionModel{
+			Title:       types.StringValue(conditionTitle),
+			Description: types.StringNull(),
+			Expression:  types.StringNull(),
+		})...)
+	}
+}
+
+// memberIndex returns the index of member, or -1. Emails aren't case sensitive.
+func memberIndex(members []string, member string) int {
+
+	for i, m := range members {
+		if strings.EqualFold(m, member) {
+			return i
+		}
+	}
+	return -1
+}
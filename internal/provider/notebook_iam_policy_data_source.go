@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &notebookIamPolicyDataSource{}
+	_ datasource.DataSourceWithConfigure = &notebookIamPolicyDataSource{}
+)
+
+// just making alias to not get confused
+type notebookIamPolicyDataSource gcpNotebookClient
+
+func NewNotebookIamPolicyDataSource() datasource.DataSource {
+	return &notebookIamPolicyDataSource{}
+}
+
+// Configure implements datasource.DataSourceWithConfigure.
+func (n *notebookIamPolicyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got %T", req.ProviderData),
+		)
+		return
+	}
+	n.provider = data
+}
+
+// Metadata implements datasource.DataSource.
+func (n *notebookIamPolicyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_iam_policy"
+}
+
+// Read implements datasource.DataSource.
+func (n *notebookIamPolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read (notebook_iam_policy_data_source) *********")
+
+	var state notebookIamPolicyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template := state.NotebookRuntimeTemplate.ValueString()
+
+	client, err := n.provider.templateClient(template)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid template name", err.Error())
+		return
+	}
+
+	policy, err := client.GetIamPolicy(template)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read IAM policy",
+			"Could not read the IAM policy of "+template+": "+err.Error(),
+		)
+		return
+	}
+
+	data, err := normaliseIamPolicyData(policy.Bindings)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read IAM policy", err.Error())
+		return
+	}
+
+	state.PolicyData = types.StringValue(data)
+	state.Etag = types.StringValue(policy.Etag)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Schema implements datasource.DataSource.
+func (n *notebookIamPolicyDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema (notebook_iam_policy_data_source) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The current IAM policy of a runtime template",
+		Attributes: map[string]schema.Attribute{
+			"notebook_runtime_template": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The full name of the runtime template",
+			},
+			"policy_data": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The policy bindings as JSON",
+			},
+			"etag": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &notebookIamPolicyResource{}
+	_ resource.ResourceWithConfigure      = &notebookIamPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &notebookIamPolicyResource{}
+	_ resource.ResourceWithImportState    = &notebookIamPolicyResource{}
+)
+
+// just making alias to not get confused
+type notebookIamPolicyResource gcpNotebookClient
+
+func NewNotebookIamPolicyResource() resource.Resource {
+	return &notebookIamPolicyResource{}
+}
+
+func (n *notebookIamPolicyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	n.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (n *notebookIamPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_iam_policy"
+}
+
+func (n *notebookIamPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+
+	var data notebookIamPolicyModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.NotebookRuntimeTemplate.IsNull() && !data.NotebookRuntimeTemplate.IsUnknown() {
+		if _, _, _, err := gcp.ParseTemplateName(data.NotebookRuntimeTemplate.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("notebook_runtime_template"), "Invalid template name", err.Error())
+		}
+	}
+
+	if !data.PolicyData.IsNull() && !data.PolicyData.IsUnknown() {
+		if _, err := parseIamPolicyData(data.PolicyData.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("policy_data"), "Invalid policy_data", err.Error())
+		}
+	}
+}
+
+// Schema implements resource.Resource.
+func (n *notebookIamPolicyResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(notebook_iam_policy_resource) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Authoritatively sets the IAM policy of a runtime template, replacing any existing policy",
+		Attributes: map[string]schema.Attribute{
+			"notebook_runtime_template": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The full name of the runtime template, e.g. `daw_notebook.example.name`",
+			},
+			"policy_data": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The policy bindings as JSON, e.g. `jsonencode({ bindings = [{ role = \"roles/aiplatform.notebookRuntimeUser\", members = [\"group:analysts@example.com\"] }] })`",
+			},
+			"etag": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Create implements resource.Resource.
+func (n *notebookIamPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(notebook_iam_policy_resource) *********")
+
+	var plan notebookIamPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	n.setPolicy(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read implements resource.Resource.
+func (n *notebookIamPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(notebook_iam_policy_resource) *********")
+
+	var state notebookIamPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template := state.NotebookRuntimeTemplate.ValueString()
+
+	client, err := n.provider.templateClient(template)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid template name", err.Error())
+		return
+	}
+
+	policy, err := client.GetIamPolicy(template)
+
+	if gcp.IsNotFound(err) {
+		tflog.Warn(ctx, "Template no longer exists, removing policy from state", map[string]interface{}{"name": template})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading IAM policy",
+			"Could not read the IAM policy of "+template+": "+err.Error(),
+		)
+		return
+	}
+
+	actual, err := normaliseIamPolicyData(policy.Bindings)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading IAM policy", err.Error())
+		return
+	}
+
+	// keep the configured formatting unless the policy really changed
+	if prior, err := n.normalisedPolicyData(state.PolicyData); err != nil || prior != actual {
+		state.PolicyData = types.StringValue(actual)
+	}
+	state.Etag = types.StringValue(policy.Etag)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update implements resource.Resource.
+func (n *notebookIamPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	tflog.Debug(ctx, "********* In Update(notebook_iam_policy_resource) *********")
+
+	var plan notebookIamPolicyModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	n.setPolicy(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete implements resource.Resource.
+func (n *notebookIamPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(notebook_iam_policy_resource) *********")
+
+	var state notebookIamPolicyModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// an empty policy removes every binding
+	_, err := n.provider.modifyTemplatePolicy(state.NotebookRuntimeTemplate.ValueString(), func(policy *gcp.Policy) error {
+		policy.Bindings = nil
+		return nil
+	})
+
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting IAM policy",
+			"Could not clear the IAM policy, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// ImportState implements resource.ResourceWithImportState, the id is the template name.
+func (n *notebookIamPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	if _, _, _, err := gcp.ParseTemplateName(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("notebook_runtime_template"), req.ID)...)
+}
+
+// setPolicy replaces the bindings of the template with policy_data
+func (n *notebookIamPolicyResource) setPolicy(ctx context.Context, plan *notebookIamPolicyModel, diags *diag.Diagnostics) {
+
+	bindings, err := parseIamPolicyData(plan.PolicyData.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("policy_data"), "Invalid policy_data", err.Error())
+		return
+	}
+
+	policy, err := n.provider.modifyTemplatePolicy(plan.NotebookRuntimeTemplate.ValueString(), func(policy *gcp.Policy) error {
+		policy.Bindings = bindings
+		return nil
+	})
+
+	if err != nil {
+		diags.AddError(
+			"Error setting IAM policy",
+			"Could not set the IAM policy, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Debug(ctx, "Set IAM policy", map[string]interface{}{"etag": policy.Etag})
+
+	plan.Etag = types.StringValue(policy.Etag)
+}
+
+func (n *notebookIamPolicyResource) normalisedPolicyData(data types.String) (string, error) {
+
+	bindings, err := parseIamPolicyData(data.ValueString())
+	if err != nil {
+		return "", err
+	}
+	return normaliseIamPolicyData(bindings)
+}
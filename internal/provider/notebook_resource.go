@@ -3,30 +3,32 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"reflect"
 	"strings"
 
 	"github.com/mpstella/terraform-provider-daw/internal/gcp"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ resource.Resource              = &notebookResource{}
-	_ resource.ResourceWithConfigure = &notebookResource{}
+	_ resource.Resource                   = &notebookResource{}
+	_ resource.ResourceWithConfigure      = &notebookResource{}
+	_ resource.ResourceWithValidateConfig = &notebookResource{}
+	_ resource.ResourceWithModifyPlan     = &notebookResource{}
+	_ resource.ResourceWithImportState    = &notebookResource{}
 )
 
 // just making alias to not get confused
@@ -40,18 +42,18 @@ func (n *notebookResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*gcp.NotebookClient)
+	data, ok := req.ProviderData.(*providerData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
-			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *gcp.NotebookClient, got: %T", req.ProviderData),
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
 		)
 
 		return
 	}
 
-	n.client = client
+	n.provider = data
 }
 
 func (n notebookResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
@@ -64,73 +66,197 @@ func (n notebookResource) ValidateConfig(ctx context.Context, req resource.Valid
 		return
 	}
 
-	// check that these values don't conflict
-	if data.IdleShutdownConfig.IdleShutdownDisabled.ValueBool() && !data.IdleShutdownConfig.IdleTimeout.IsNull() {
-		resp.Diagnostics.AddAttributeWarning(
-			path.Root("idle_shutdown_disabled"),
-			"idle_shutdown_disabled can't be set to True and have a value in idle_timeout",
-			"Expected idle_timeout to be nil if idle_shutdown_disabled is set to True.",
-		)
+	// the specs can come from a preset, anything it doesn't set is required
+	if data.Preset.IsNull() {
+		data.requireSpecs(&resp.Diagnostics)
+	} else if n.provider != nil && !data.Preset.IsUnknown() {
+		if _, ok := n.provider.presets[data.Preset.ValueString()]; !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("preset"),
+				"Unknown preset",
+				fmt.Sprintf("%q is not a preset of the provider, expected one of: %s", data.Preset.ValueString(), presetNames(n.provider.presets)),
+			)
+		}
 	}
 
-	// check the idleTimeout values are correctly applied
-	if !data.IdleShutdownConfig.IdleTimeout.IsNull() {
-		val := data.IdleShutdownConfig.IdleTimeout.ValueString()
-		if !strings.HasSuffix(val, "s") {
-			resp.Diagnostics.AddAttributeWarning(
-				path.Root("idle_timeout"),
-				"idle_timeout must end in 's'",
-				"Expected idle_timeout end in 's' as it is defined in seconds",
-			)
+	machineSpec := valueOrZero(data.MachineSpec)
+
+	// the preset may hold the accelerator type
+	specMachineSpec := data.MachineSpec
+	if !data.Preset.IsNull() {
+		specMachineSpec = nil
+	}
+	for _, w := range checkSpecs(specMachineSpec, data.NetworkSpec, data.IdleShutdownConfig) {
+		resp.Diagnostics.AddAttributeWarning(w.path, w.summary, w.detail)
+	}
+
+	// the provider overrides are only known once it is configured
+	table, strict := builtinAcceleratorCompatibility, false
+	if n.provider != nil {
+		table, strict = n.provider.acceleratorCompatibility, true
+	}
+	resp.Diagnostics.Append(validateMachineSpec(table, strict, machineSpec)...)
+}
+
+// requireSpecs reports the spec attributes that are missing, either from the
+// configuration or once a preset has been applied
+func (data *notebookModel) requireSpecs(diags *diag.Diagnostics) {
+
+	const detail = "Set it on the resource or use a preset that does"
+
+	if data.MachineSpec == nil {
+		diags.AddAttributeError(path.Root("machine_spec"), "Missing machine_spec", detail)
+	} else if data.MachineSpec.MachineType.IsNull() {
+		diags.AddAttributeError(path.Root("machine_spec").AtName("machine_type"), "Missing machine_type", detail)
+	}
+
+	if data.DataPersistentDiskSpec == nil {
+		diags.AddAttributeError(path.Root("data_persistent_disk_spec"), "Missing data_persistent_disk_spec", detail)
+		return
+	}
+	if data.DataPersistentDiskSpec.DiskType.IsNull() {
+		diags.AddAttributeError(path.Root("data_persistent_disk_spec").AtName("disk_type"), "Missing disk_type", detail)
+	}
+	if data.DataPersistentDiskSpec.DiskSizeGb.IsNull() {
+		diags.AddAttributeError(path.Root("data_persistent_disk_spec").AtName("disk_size_gb"), "Missing disk_size_gb", detail)
+	}
+}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan.
+func (n *notebookResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	tflog.Debug(ctx, "********* In ModifyPlan(notebook_resource) *********")
+
+	var state notebookModel
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
-		val = strings.TrimSuffix(val, "s")
-		num, err := strconv.Atoi(val)
-		if err != nil {
-			resp.Diagnostics.AddAttributeWarning(
-				path.Root("idle_timeout"),
-				"idle_timeout must end in 's' and be a valid integer",
-				"Expected idle_timeout end in 's' as it is defined in seconds as an integer",
+	}
+
+	// nothing else to plan when the resource is destroyed
+	if req.Plan.Raw.IsNull() {
+		if state.DeletionProtection.ValueBool() {
+			resp.Diagnostics.AddWarning(
+				"Protected template would be destroyed",
+				fmt.Sprintf("The template %q has deletion_protection enabled, the apply will fail when deleting it. Apply deletion_protection = false first to destroy it.", state.DisplayName.ValueString()),
 			)
-		} else {
-			if num < 600 || num > 86400 {
-				// [600, 86400]
-				resp.Diagnostics.AddAttributeWarning(
-					path.Root("idle_timeout"),
-					"idle_timeout must end in 's' and be a valid integer between 600 and 86400",
-					"Expected idle_timeout to be between 600 and 86400",
-				)
-			}
+		}
+		return
+	}
+
+	var defaultLabels map[string]string
+	var ignore ignoreLabels
+	if n.provider != nil {
+		defaultLabels = n.provider.defaultLabels
+		ignore = n.provider.ignoreLabels
+	}
+
+	planLabels(ctx, defaultLabels, ignore, req, resp)
+
+	if n.provider == nil || resp.Diagnostics.HasError() {
+		return
+	}
+
+	n.provider.resolvePresets(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan notebookModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// configured specs were checked by ValidateConfig, those merged with a
+	// preset only can be now
+	if !plan.Preset.IsNull() {
+		plan.requireSpecs(&resp.Diagnostics)
+		if plan.MachineSpec != nil {
+			resp.Diagnostics.Append(validateMachineSpec(n.provider.acceleratorCompatibility, true, *plan.MachineSpec)...)
 		}
 	}
 
-	// if enable_internet_access is false then both network and subdomain need to be set
-	if !data.NetworkSpec.EnableInternetAccess.ValueBool() {
-		if data.NetworkSpec.Network.IsNull() {
-			resp.Diagnostics.AddAttributeWarning(
-				path.Root("network"),
-				"network can't be nil if enable_internet_access is false",
-				"Expected network to be configured",
-			)
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+
+	// only a configured is_default claims the location, daw_notebook_default
+	// may have made the template the default
+	var isDefault types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("is_default"), &isDefault)...)
+
+	if isDefault.ValueBool() {
+		if err := n.provider.defaultTemplates.claim(project, location, n.provider.defaultTemplates.claimant("daw_notebook", state.Name)); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("is_default"), "Second default template", err.Error())
 		}
-		if data.NetworkSpec.Subnetwork.IsNull() {
-			resp.Diagnostics.AddAttributeWarning(
-				path.Root("subnetwork"),
-				"subnetwork can't be nil if enable_internet_access is false",
-				"Expected subnetwork to be configured",
+	}
+
+	// the template is deleted before its replacement is created
+	if !req.State.Raw.IsNull() && state.DeletionProtection.ValueBool() {
+
+		var config notebookModel
+		resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+		if replaced := replacedAttributes(config, plan, state, resp.RequiresReplace); len(replaced) > 0 {
+			resp.Diagnostics.AddWarning(
+				"Protected template would be replaced",
+				fmt.Sprintf("Changes to %s replace the template %q, which has deletion_protection enabled so the apply will fail when deleting it. Apply deletion_protection = false on its own first to allow the replacement.", strings.Join(replaced, ", "), state.DisplayName.ValueString()),
 			)
 		}
 	}
 
-	// check machine type accelerator settings
-	if data.MachineSpec.AcceleratorType.IsNull() && !data.MachineSpec.AcceleratorCount.IsNull() {
-		resp.Diagnostics.AddAttributeWarning(
-			path.Root("accelerator_count"),
-			"accelerator_count must be nil if accelerator_type is nil",
-			"Expected accelerator_count to not be configured",
-		)
+	hourly, daily := n.provider.estimateCosts(ctx, location, plan.MachineSpec, plan.DataPersistentDiskSpec, plan.IdleShutdownConfig)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_hourly_cost"), hourly)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("worst_case_daily_cost"), daily)...)
+
+	// guardrails need the provider configuration, so they are enforced when
+	// planning only and not by terraform validate
+	if n.provider.guardrails != nil {
+		labels, known, diags := guardrailLabels(ctx, defaultLabels, plan.Labels)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(n.provider.guardrails.check(plan, labels, known)...)
 	}
 }
 
+// replacedAttributes lists the attributes whose change replaces the template.
+// The replacements requested by the schema plan modifiers aren't passed to
+// ModifyPlan, so they are worked out again from the configuration.
+func replacedAttributes(config notebookModel, plan notebookModel, state notebookModel, requiresReplace path.Paths) []string {
+
+	var replaced []string
+
+	for _, at := range requiresReplace {
+		replaced = append(replaced, at.String())
+	}
+
+	// RequiresReplaceIfConfigured
+	ifConfigured := []struct {
+		name                string
+		config, plan, state attr.Value
+	}{
+		{"project", config.Project, plan.Project, state.Project},
+		{"location", config.Location, plan.Location, state.Location},
+		{"display_name", config.DisplayName, plan.DisplayName, state.DisplayName},
+		{"description", config.Description, plan.Description, state.Description},
+		{"is_default", config.IsDefault, plan.IsDefault, state.IsDefault},
+		{"enable_secure_boot", config.EnableSecureBoot, plan.EnableSecureBoot, state.EnableSecureBoot},
+	}
+
+	for _, a := range ifConfigured {
+		if !a.config.IsNull() && !a.plan.Equal(a.state) {
+			replaced = append(replaced, a.name)
+		}
+	}
+
+	// RequiresReplace
+	if !reflect.DeepEqual(plan.NetworkSpec, state.NetworkSpec) {
+		replaced = append(replaced, "network_spec")
+	}
+
+	return replaced
+}
+
 func NewNotebookResource() resource.Resource {
 	return &notebookResource{}
 }
@@ -155,11 +281,6 @@ func (n *notebookResource) Create(ctx context.Context, req resource.CreateReques
 		MachineSpec: &gcp.MachineSpec{
 			MachineType: plan.MachineSpec.MachineType.ValueStringPointer(),
 		},
-		NetworkSpec: &gcp.NetworkSpec{
-			EnableInternetAccess: plan.NetworkSpec.EnableInternetAccess.ValueBoolPointer(),
-			Network:              plan.NetworkSpec.Network.ValueStringPointer(),
-			Subnetwork:           plan.NetworkSpec.Subnetwork.ValueStringPointer(),
-		},
 		ShieldedVmConfig: &gcp.ShieldedVmConfig{
 			EnableSecureBoot: plan.EnableSecureBoot.ValueBoolPointer(),
 		},
@@ -167,10 +288,21 @@ func (n *notebookResource) Create(ctx context.Context, req resource.CreateReques
 			DiskType:   plan.DataPersistentDiskSpec.DiskType.ValueStringPointer(),
 			DiskSizeGb: plan.DataPersistentDiskSpec.DiskSizeGb.ValueStringPointer(),
 		},
-		IdleShutdownConfig: &gcp.IdleShutdownConfig{
+	}
+
+	if plan.NetworkSpec != nil {
+		notebook.NetworkSpec = &gcp.NetworkSpec{
+			EnableInternetAccess: plan.NetworkSpec.EnableInternetAccess.ValueBoolPointer(),
+			Network:              plan.NetworkSpec.Network.ValueStringPointer(),
+			Subnetwork:           plan.NetworkSpec.Subnetwork.ValueStringPointer(),
+		}
+	}
+
+	if plan.IdleShutdownConfig != nil {
+		notebook.IdleShutdownConfig = &gcp.IdleShutdownConfig{
 			IdleTimeout:          plan.IdleShutdownConfig.IdleTimeout.ValueStringPointer(),
 			IdleShutdownDisabled: plan.IdleShutdownConfig.IdleShutdownDisabled.ValueBoolPointer(),
-		},
+		}
 	}
 
 	// accelerator_type spec can be nil or set depending on machine type
@@ -190,21 +322,32 @@ func (n *notebookResource) Create(ctx context.Context, req resource.CreateReques
 		}
 	}
 
-	if !plan.Labels.IsNull() {
+	// terraform_labels holds the labels merged with the provider default_labels
+	if !plan.TerraformLabels.IsNull() {
 
-		tflog.Debug(ctx, "********* In Create(plan.Labels IsNotNull()) *********")
+		tflog.Debug(ctx, "********* In Create(plan.TerraformLabels IsNotNull()) *********")
 
 		labels := make(map[string]string)
-		plan.Labels.ElementsAs(ctx, &labels, false)
+		plan.TerraformLabels.ElementsAs(ctx, &labels, false)
 		notebook.Labels = &labels
 	} else {
-		tflog.Debug(ctx, "********* In Create(plan.Labels IsNull()) *********")
+		tflog.Debug(ctx, "********* In Create(plan.TerraformLabels IsNull()) *********")
 	}
 
 	nas, _ := notebook.AsString()
 	tflog.Debug(ctx, nas)
 
-	new_notebook, err := n.client.CreateNotebook(&notebook)
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+	plan.Project = types.StringValue(project)
+	plan.Location = types.StringValue(location)
+
+	// making a new default clears the flag on the old one, the same as daw_notebook_default does
+	if plan.IsDefault.ValueBool() {
+		n.provider.locks.Lock(defaultTemplateLockKey(project, location))
+		defer n.provider.locks.Unlock(defaultTemplateLockKey(project, location))
+	}
+
+	new_notebook, err := n.provider.notebookClient(project, location).CreateNotebook(ctx, &notebook)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -216,6 +359,17 @@ func (n *notebookResource) Create(ctx context.Context, req resource.CreateReques
 
 	plan.Name = types.StringPointerValue(new_notebook.Name)
 
+	if plan.IsDefault.IsUnknown() {
+		plan.IsDefault = types.BoolValue(new_notebook.IsDefault != nil && *new_notebook.IsDefault)
+	}
+
+	var effectiveLabels map[string]string
+	if new_notebook.Labels != nil {
+		effectiveLabels = n.provider.ignoreLabels.filter(*new_notebook.Labels)
+	}
+	plan.EffectiveLabels, diags = labelsMapValue(ctx, effectiveLabels)
+	resp.Diagnostics.Append(diags...)
+
 	// Set state to fully populated data
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -236,8 +390,18 @@ func (n *notebookResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Template is protected",
+			fmt.Sprintf("Cannot delete the template %q (%s) while deletion_protection is enabled, set it to false and apply before destroying or replacing the template.", state.DisplayName.ValueString(), state.Name.ValueString()),
+		)
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
 	// going to ignore deletes as this only occurs when resource has already been deleted
-	n.client.DeleteNotebookRuntimeTemplate(state.Name.ValueString())
+	n.provider.notebookClient(project, location).DeleteNotebookRuntimeTemplate(state.Name.ValueString())
 }
 
 // Metadata implements resource.Resource.
@@ -258,7 +422,10 @@ func (n *notebookResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	notebook, err := n.client.GetNotebook(state.Name.ValueString())
+	// state from before project and location were tracked falls back on the provider values
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
+	notebook, err := n.provider.notebookClient(project, location).GetNotebook(state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Reading GCP Notebooks",
@@ -267,39 +434,63 @@ func (n *notebookResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	// only the labels tracked by Terraform are refreshed, the preset is only
+	// known to Terraform
+	priorLabels, priorTerraformLabels, preset := state.Labels, state.TerraformLabels, state.Preset
+
+	// imported templates, and those from before the attribute existed, are protected
+	deletionProtection := state.DeletionProtection
+	if deletionProtection.IsNull() {
+		deletionProtection = types.BoolValue(true)
+	}
+
 	// Overwrite with refreshed state
 	state = notebookModel{
 		Name:        types.StringPointerValue(notebook.Name),
+		Project:     types.StringValue(project),
+		Location:    types.StringValue(location),
 		DisplayName: types.StringPointerValue(notebook.DisplayName),
 		Description: types.StringPointerValue(notebook.Description),
 		IsDefault:   types.BoolPointerValue(notebook.IsDefault),
-		DataPersistentDiskSpec: notebookDataPersistentDiskSpecModel{
+		Preset:      preset,
+
+		DeletionProtection: deletionProtection,
+	}
+
+	if notebook.DataPersistentDiskSpec != nil {
+		state.DataPersistentDiskSpec = &notebookDataPersistentDiskSpecModel{
 			DiskType:   types.StringPointerValue(notebook.DataPersistentDiskSpec.DiskType),
 			DiskSizeGb: types.StringPointerValue(notebook.DataPersistentDiskSpec.DiskSizeGb),
-		},
-		MachineSpec: notebookMachineSpecModel{
+		}
+	}
+
+	if notebook.MachineSpec != nil {
+		state.MachineSpec = &notebookMachineSpecModel{
 			MachineType:      types.StringPointerValue(notebook.MachineSpec.MachineType),
 			AcceleratorType:  types.StringPointerValue(notebook.MachineSpec.AcceleratorType),
 			AcceleratorCount: types.Int64PointerValue(notebook.MachineSpec.AcceleratorCount),
-		},
-		IdleShutdownConfig: notebookIdleShutdownConfigModel{
+		}
+	}
+
+	if notebook.IdleShutdownConfig != nil {
+		state.IdleShutdownConfig = &notebookIdleShutdownConfigModel{
 			IdleTimeout:          types.StringPointerValue(notebook.IdleShutdownConfig.IdleTimeout),
-			IdleShutdownDisabled: types.BoolPointerValue(notebook.IdleShutdownConfig.IdleShutdownDisabled),
-		},
-		NetworkSpec: notebookNetworkSpecModel{
+			IdleShutdownDisabled: types.BoolValue(notebook.IdleShutdownConfig.IdleShutdownDisabled != nil && *notebook.IdleShutdownConfig.IdleShutdownDisabled),
+		}
+	}
+
+	if notebook.NetworkSpec != nil {
+		state.NetworkSpec = &notebookNetworkSpecModel{
 			EnableInternetAccess: types.BoolPointerValue(notebook.NetworkSpec.EnableInternetAccess),
 			Network:              types.StringPointerValue(notebook.NetworkSpec.Network),
 			Subnetwork:           types.StringPointerValue(notebook.NetworkSpec.Subnetwork),
-		},
+		}
 	}
 
 	if notebook.IsDefault == nil {
 		state.IsDefault = types.BoolValue(false)
 	}
-	if notebook.IdleShutdownConfig.IdleShutdownDisabled == nil {
-		state.IdleShutdownConfig.IdleShutdownDisabled = types.BoolValue(false)
-	}
-	if notebook.ShieldedVmConfig.EnableSecureBoot == nil {
+	if notebook.ShieldedVmConfig == nil || notebook.ShieldedVmConfig.EnableSecureBoot == nil {
 		state.EnableSecureBoot = types.BoolValue(false)
 	}
 
@@ -309,14 +500,22 @@ func (n *notebookResource) Read(ctx context.Context, req resource.ReadRequest, r
 		state.KmsKeyName = types.StringPointerValue(notebook.EncryptionSpec.KmsKeyName)
 	}
 
-	if notebook.Labels == nil {
-		state.Labels = types.MapNull(types.StringType)
-	} else {
-		var diags diag.Diagnostics
-		state.Labels, diags = basetypes.NewMapValueFrom(ctx, types.StringType, *notebook.Labels)
-		resp.Diagnostics.Append(diags...)
+	labels := make(map[string]string)
+	if notebook.Labels != nil {
+		labels = n.provider.ignoreLabels.filter(*notebook.Labels)
 	}
 
+	state.Labels, diags = refreshLabels(ctx, priorLabels, labels)
+	resp.Diagnostics.Append(diags...)
+
+	state.TerraformLabels, diags = refreshLabels(ctx, priorTerraformLabels, labels)
+	resp.Diagnostics.Append(diags...)
+
+	state.EffectiveLabels, diags = labelsMapValue(ctx, labels)
+	resp.Diagnostics.Append(diags...)
+
+	state.EstimatedHourlyCost, state.WorstCaseDailyCost = n.provider.estimateCosts(ctx, location, state.MachineSpec, state.DataPersistentDiskSpec, state.IdleShutdownConfig)
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -325,6 +524,21 @@ func (n *notebookResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 }
 
+// ImportState implements resource.ResourceWithImportState, the id is the
+// template name which also carries its project and location.
+func (n *notebookResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+
+	project, location, _, err := gcp.ParseTemplateName(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project"), project)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("location"), location)...)
+}
+
 // Schema implements resource.Resource.
 func (n *notebookResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 
@@ -338,6 +552,24 @@ func (n *notebookResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The project to create the template in, defaults to the provider project",
+			},
+			"location": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The location to create the template in, defaults to the provider location",
+			},
 			"display_name": schema.StringAttribute{
 				Required: true,
 				PlanModifiers: []planmodifier.String{
@@ -360,7 +592,8 @@ func (n *notebookResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 					boolplanmodifier.UseStateForUnknown(),
 					boolplanmodifier.RequiresReplaceIfConfigured(),
 				},
-				Default: booldefault.StaticBool(false),
+				MarkdownDescription: "Whether the template is the default of its location. Don't set it on a template made the default by `daw_notebook_default`, " +
+					"the two are mutually exclusive",
 			},
 			"enable_secure_boot": schema.BoolAttribute{
 				Computed: true,
@@ -373,48 +606,47 @@ func (n *notebookResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 			"kms_key_name": schema.StringAttribute{
 				Optional: true,
 			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+				MarkdownDescription: "Whether Terraform may delete the template, also when replacing it. Defaults to `true`, set it to `false` and apply before destroying the template.",
+			},
+			"preset": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The name of a provider `preset` filling `machine_spec`, `data_persistent_disk_spec` and `idle_shutdown_config`, attributes set on the resource take precedence",
+			},
 			"machine_spec": schema.SingleNestedAttribute{
-				Required: true,
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Required unless given by the `preset`. Attributes neither configured nor given by the preset keep their current value.",
 				Attributes: map[string]schema.Attribute{
 					"machine_type": schema.StringAttribute{
-						Required: true,
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.UseStateForUnknown(),
-							stringplanmodifier.RequiresReplaceIfConfigured(),
-						},
+						Optional: true,
+						Computed: true,
 					},
 					"accelerator_type": schema.StringAttribute{
 						Optional: true,
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.UseStateForUnknown(),
-							stringplanmodifier.RequiresReplaceIfConfigured(),
-						},
+						Computed: true,
 					},
 					"accelerator_count": schema.Int64Attribute{
 						Optional: true,
-						PlanModifiers: []planmodifier.Int64{
-							int64planmodifier.UseStateForUnknown(),
-							int64planmodifier.RequiresReplaceIfConfigured(),
-						},
+						Computed: true,
 					},
 				},
 			},
 			"data_persistent_disk_spec": schema.SingleNestedAttribute{
-				Required: true,
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Required unless given by the `preset`",
 				Attributes: map[string]schema.Attribute{
 					"disk_type": schema.StringAttribute{
-						Required: true,
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.UseStateForUnknown(),
-							stringplanmodifier.RequiresReplaceIfConfigured(),
-						},
+						Optional: true,
+						Computed: true,
 					},
 					"disk_size_gb": schema.StringAttribute{
-						Required: true,
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.UseStateForUnknown(),
-							stringplanmodifier.RequiresReplaceIfConfigured(),
-						},
+						Optional: true,
+						Computed: true,
 					},
 				},
 			},
@@ -447,36 +679,42 @@ func (n *notebookResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 			},
 			"idle_shutdown_config": schema.SingleNestedAttribute{
 				Optional: true,
-				PlanModifiers: []planmodifier.Object{
-					objectplanmodifier.UseStateForUnknown(),
-					objectplanmodifier.RequiresReplace(),
-				},
+				Computed: true,
 				Attributes: map[string]schema.Attribute{
 					"idle_timeout": schema.StringAttribute{
 						Optional: true,
-						PlanModifiers: []planmodifier.String{
-							stringplanmodifier.UseStateForUnknown(),
-							stringplanmodifier.RequiresReplaceIfConfigured(),
-						},
+						Computed: true,
 					},
 					"idle_shutdown_disabled": schema.BoolAttribute{
 						Optional: true,
 						Computed: true,
-						PlanModifiers: []planmodifier.Bool{
-							boolplanmodifier.UseStateForUnknown(),
-							boolplanmodifier.RequiresReplaceIfConfigured(),
-						},
-						Default: booldefault.StaticBool(false),
 					},
 				},
 			},
+			"estimated_hourly_cost": schema.Float64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The estimated on-demand cost of an hour of runtime (machine, accelerators and data disk), null when the price catalogue doesn't cover the template",
+			},
+			"worst_case_daily_cost": schema.Float64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The estimated cost of a runtime left idle for a day, it runs until `idle_timeout` (3 hours by default) or all day when idle shutdown is disabled",
+			},
 			"labels": schema.MapAttribute{
-				Description: "A set of key/value label pairs to assign to the resource.",
+				Description: "A set of key/value label pairs to assign to the resource. These are non-authoritative, labels added outside of Terraform are left alone.",
 				Optional:    true,
 				ElementType: types.StringType,
+			},
+			"terraform_labels": schema.MapAttribute{
+				Description: "The labels configured on the resource merged with the provider default_labels.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"effective_labels": schema.MapAttribute{
+				Description: "All the labels present on the template, including those applied outside of Terraform.",
+				Computed:    true,
+				ElementType: types.StringType,
 				PlanModifiers: []planmodifier.Map{
 					mapplanmodifier.UseStateForUnknown(),
-					mapplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 		},
@@ -495,21 +733,35 @@ func (n *notebookResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
-	notebook := gcp.NotebookRuntimeTemplate{
-		Name: plan.Name.ValueStringPointer(),
-		EncryptionSpec: &gcp.EncryptionSpec{
-			KmsKeyName: plan.KmsKeyName.ValueStringPointer(),
-		},
+	var state notebookModel
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	err := n.client.UpdateNotebook(&notebook)
+	// the encryption key is the only field that can be patched, anything else
+	// changing in place (e.g. terraform_labels catching up) only touches state
+	if !plan.KmsKeyName.Equal(state.KmsKeyName) {
 
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating template",
-			"Could update template, unexpected error: "+err.Error(),
-		)
-		return
+		notebook := gcp.NotebookRuntimeTemplate{
+			Name: plan.Name.ValueStringPointer(),
+			EncryptionSpec: &gcp.EncryptionSpec{
+				KmsKeyName: plan.KmsKeyName.ValueStringPointer(),
+			},
+		}
+
+		project, location := n.provider.projectLocation(plan.Project, plan.Location)
+
+		err := n.provider.notebookClient(project, location).UpdateNotebook(&notebook)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating template",
+				"Could update template, unexpected error: "+err.Error(),
+			)
+			return
+		}
 	}
 
 	// Set state to fully populated data
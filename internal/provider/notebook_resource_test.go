@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func notebookSchema(t *testing.T) schema.Schema {
+
+	var resp resource.SchemaResponse
+	(&notebookResource{}).Schema(context.Background(), resource.SchemaRequest{}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected errors: %v", resp.Diagnostics)
+	}
+	return resp.Schema
+}
+
+// notebookState holds model in a state of the daw_notebook schema
+func notebookState(t *testing.T, model notebookModel) tfsdk.State {
+
+	ctx := context.Background()
+	s := notebookSchema(t)
+
+	labels := types.MapNull(types.StringType)
+	for _, m := range []*types.Map{&model.Labels, &model.TerraformLabels, &model.EffectiveLabels} {
+		if m.ElementType(ctx) == nil {
+			*m = labels
+		}
+	}
+
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+	return state
+}
+
+// replacing returns the top level attributes of the schema whose change
+// replaces the template
+func replacing(t *testing.T) []string {
+
+	ctx := context.Background()
+
+	replace := map[string]bool{
+		stringplanmodifier.RequiresReplace().Description(ctx):             true,
+		stringplanmodifier.RequiresReplaceIfConfigured().Description(ctx): true,
+		boolplanmodifier.RequiresReplace().Description(ctx):               true,
+		boolplanmodifier.RequiresReplaceIfConfigured().Description(ctx):   true,
+		objectplanmodifier.RequiresReplace().Description(ctx):             true,
+	}
+
+	var names []string
+
+	for name, attribute := range notebookSchema(t).Attributes {
+
+		var descriptions []string
+
+		switch a := attribute.(type) {
+		case schema.StringAttribute:
+			for _, m := range a.PlanModifiers {
+				descriptions = append(descriptions, m.Description(ctx))
+			}
+		case schema.BoolAttribute:
+			for _, m := range a.PlanModifiers {
+				descriptions = append(descriptions, m.Description(ctx))
+			}
+		case schema.SingleNestedAttribute:
+			for _, m := range a.PlanModifiers {
+				descriptions = append(descriptions, m.Description(ctx))
+			}
+		}
+
+		for _, description := range descriptions {
+			if replace[description] {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func TestReplacedAttributesMatchSchema(t *testing.T) {
+
+	state := notebookModel{
+		Project:          types.StringValue("p1"),
+		Location:         types.StringValue("us-central1"),
+		DisplayName:      types.StringValue("gpu"),
+		Description:      types.StringValue("old"),
+		IsDefault:        types.BoolValue(false),
+		EnableSecureBoot: types.BoolValue(false),
+		NetworkSpec:      &notebookNetworkSpecModel{EnableInternetAccess: types.BoolValue(true)},
+	}
+
+	// every attribute is configured and changes
+	plan := notebookModel{
+		Project:          types.StringValue("p2"),
+		Location:         types.StringValue("europe-west4"),
+		DisplayName:      types.StringValue("cpu"),
+		Description:      types.StringValue("new"),
+		IsDefault:        types.BoolValue(true),
+		EnableSecureBoot: types.BoolValue(true),
+		NetworkSpec:      &notebookNetworkSpecModel{EnableInternetAccess: types.BoolValue(false)},
+	}
+
+	got := replacedAttributes(plan, plan, state, nil)
+	sort.Strings(got)
+
+	if want := replacing(t); !reflect.DeepEqual(got, want) {
+		t.Fatalf("the schema replaces the template on changes to %v, replacedAttributes reports %v", want, got)
+	}
+}
+
+func TestReplacedAttributes(t *testing.T) {
+
+	state := notebookModel{
+		DisplayName: types.StringValue("gpu"),
+		Description: types.StringValue("old"),
+		NetworkSpec: &notebookNetworkSpecModel{EnableInternetAccess: types.BoolValue(true)},
+	}
+
+	tests := []struct {
+		name            string
+		config, plan    notebookModel
+		requiresReplace path.Paths
+		want            []string
+	}{
+		{
+			name:   "nothing changes",
+			config: state, plan: state,
+		},
+		{
+			name:   "configured change",
+			config: notebookModel{Description: types.StringValue("new")},
+			plan:   notebookModel{DisplayName: types.StringValue("gpu"), Description: types.StringValue("new"), NetworkSpec: state.NetworkSpec},
+			want:   []string{"description"},
+		},
+		{
+			name:   "change that isn't configured",
+			config: notebookModel{},
+			plan:   notebookModel{DisplayName: types.StringValue("gpu"), Description: types.StringValue("new"), NetworkSpec: state.NetworkSpec},
+		},
+		{
+			name:   "network_spec removed",
+			config: notebookModel{},
+			plan:   notebookModel{DisplayName: types.StringValue("gpu"), Description: types.StringValue("old")},
+			want:   []string{"network_spec"},
+		},
+		{
+			name:   "replacements requested by the framework come first",
+			config: state, plan: state,
+			requiresReplace: path.Paths{path.Root("kms_key_name")},
+			want:            []string{"kms_key_name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replacedAttributes(tt.config, tt.plan, state, tt.requiresReplace); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNotebookDeletionProtection(t *testing.T) {
+
+	ctx := context.Background()
+
+	protected := notebookState(t, notebookModel{
+		Name:               types.StringValue("projects/p/locations/l/notebookRuntimeTemplates/t"),
+		DisplayName:        types.StringValue("gpu"),
+		DeletionProtection: types.BoolValue(true),
+	})
+
+	t.Run("destroy plan warns", func(t *testing.T) {
+
+		s := notebookSchema(t)
+		plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+
+		req := resource.ModifyPlanRequest{State: protected, Plan: plan}
+		resp := resource.ModifyPlanResponse{Plan: plan}
+
+		(&notebookResource{}).ModifyPlan(ctx, req, &resp)
+
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+			t.Fatalf("expected a single warning, got %v", resp.Diagnostics)
+		}
+		if summary := resp.Diagnostics.Warnings()[0].Summary(); summary != "Protected template would be destroyed" {
+			t.Fatalf("unexpected warning %q", summary)
+		}
+	})
+
+	t.Run("delete fails", func(t *testing.T) {
+
+		// no provider, the template must not be deleted
+		var resp resource.DeleteResponse
+		(&notebookResource{}).Delete(ctx, resource.DeleteRequest{State: protected}, &resp)
+
+		if resp.Diagnostics.ErrorsCount() != 1 {
+			t.Fatalf("expected a single error, got %v", resp.Diagnostics)
+		}
+		if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, `"gpu"`) {
+			t.Fatalf("expected the display name in %q", detail)
+		}
+	})
+}
@@ -0,0 +1,369 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &notebookRuntimeResource{}
+	_ resource.ResourceWithConfigure      = &notebookRuntimeResource{}
+	_ resource.ResourceWithValidateConfig = &notebookRuntimeResource{}
+)
+
+// just making alias to not get confused
+type notebookRuntimeResource gcpNotebookClient
+
+func NewNotebookRuntimeResource() resource.Resource {
+	return &notebookRuntimeResource{}
+}
+
+func (n *notebookRuntimeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	n.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (n *notebookRuntimeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_runtime"
+}
+
+func (n *notebookRuntimeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+
+	var data notebookRuntimeModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.DesiredState.IsNull() && !data.DesiredState.IsUnknown() {
+		if state := data.DesiredState.ValueString(); state != gcp.RuntimeStateRunning && state != gcp.RuntimeStateStopped {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("desired_state"),
+				"desired_state must be either RUNNING or STOPPED",
+				fmt.Sprintf("Expected desired_state to be RUNNING or STOPPED, got: %s", state),
+			)
+		}
+	}
+}
+
+// Schema implements resource.Resource.
+func (n *notebookRuntimeResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(notebook_runtime_resource) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A Colab Enterprise runtime assigned to a user from a runtime template",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The project to assign the runtime in, defaults to the provider project",
+			},
+			"location": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The location to assign the runtime in, defaults to the provider location",
+			},
+			"notebook_runtime_template": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The full name of the runtime template, e.g. `daw_notebook.example.name`",
+			},
+			"runtime_user": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The email of the user the runtime is assigned to",
+			},
+			"display_name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"desired_state": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(gcp.RuntimeStateRunning),
+				MarkdownDescription: "Either `RUNNING` (default) or `STOPPED`, the runtime is started or stopped in place",
+			},
+			"runtime_state": schema.StringAttribute{
+				Computed: true,
+			},
+			"health_state": schema.StringAttribute{
+				Computed: true,
+			},
+			"proxy_uri": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"expiration_time": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Create implements resource.Resource.
+func (n *notebookRuntimeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(notebook_runtime_resource) *********")
+
+	var plan notebookRuntimeModel
+	diags := req.Plan.Get(ctx, &plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+	plan.Project = types.StringValue(project)
+	plan.Location = types.StringValue(location)
+
+	client := n.provider.notebookClient(project, location)
+
+	runtime, err := client.AssignNotebookRuntime(ctx, &gcp.AssignNotebookRuntimeRequest{
+		NotebookRuntimeTemplate: plan.NotebookRuntimeTemplate.ValueString(),
+		NotebookRuntime: &gcp.NotebookRuntime{
+			RuntimeUser: plan.RuntimeUser.ValueStringPointer(),
+			DisplayName: plan.DisplayName.ValueStringPointer(),
+			Description: plan.Description.ValueStringPointer(),
+		},
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error assigning runtime",
+			"Could not assign runtime, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// save what we have so a failure below doesn't orphan the runtime,
+	// a newly assigned runtime is running
+	desiredState := plan.DesiredState
+	plan.Name = types.StringPointerValue(runtime.Name)
+	plan.DesiredState = types.StringValue(gcp.RuntimeStateRunning)
+	plan.refresh(runtime)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	runtime, err = n.applyDesiredState(ctx, client, plan.Name.ValueString(), desiredState.ValueString(), plan.DesiredState)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error stopping runtime",
+			"Could not stop runtime, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.DesiredState = desiredState
+	plan.refresh(runtime)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read implements resource.Resource.
+func (n *notebookRuntimeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(notebook_runtime_resource) *********")
+
+	var state notebookRuntimeModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
+	runtime, err := n.provider.notebookClient(project, location).GetNotebookRuntime(state.Name.ValueString())
+
+	if gcp.IsNotFound(err) {
+		tflog.Warn(ctx, "Runtime no longer exists, removing from state", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading GCP Notebook Runtime",
+			"Could not read runtime with name "+state.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Project = types.StringValue(project)
+	state.Location = types.StringValue(location)
+	state.RuntimeUser = types.StringPointerValue(runtime.RuntimeUser)
+	state.DisplayName = types.StringPointerValue(runtime.DisplayName)
+	state.Description = types.StringPointerValue(runtime.Description)
+
+	if runtime.NotebookRuntimeTemplateRef != nil {
+		state.NotebookRuntimeTemplate = types.StringPointerValue(runtime.NotebookRuntimeTemplateRef.NotebookRuntimeTemplate)
+	}
+
+	state.refresh(runtime)
+
+	// surface a runtime started or stopped outside of Terraform (or by the
+	// idle shutdown) as a change to desired_state
+	if runtimeState := stringOrEmpty(runtime.RuntimeState); runtimeState == gcp.RuntimeStateRunning || runtimeState == gcp.RuntimeStateStopped {
+		state.DesiredState = types.StringValue(runtimeState)
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update implements resource.Resource.
+func (n *notebookRuntimeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	tflog.Debug(ctx, "********* In Update(notebook_runtime_resource) *********")
+
+	var plan, state notebookRuntimeModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+
+	// desired_state is the only attribute updated in place
+	runtime, err := n.applyDesiredState(ctx, n.provider.notebookClient(project, location), plan.Name.ValueString(), plan.DesiredState.ValueString(), state.DesiredState)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating runtime",
+			"Could not change the runtime state, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.refresh(runtime)
+
+	// Set state to fully populated data
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete implements resource.Resource.
+func (n *notebookRuntimeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(notebook_runtime_resource) *********")
+
+	var state notebookRuntimeModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
+	err := n.provider.notebookClient(project, location).DeleteNotebookRuntime(ctx, state.Name.ValueString())
+
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting runtime",
+			"Could not delete runtime, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// applyDesiredState starts or stops the runtime when desired differs from
+// current, returning the refreshed runtime
+func (n *notebookRuntimeResource) applyDesiredState(ctx context.Context, client *gcp.NotebookClient, name string, desired string, current types.String) (*gcp.NotebookRuntime, error) {
+
+	var err error
+
+	if desired != current.ValueString() {
+		switch desired {
+		case gcp.RuntimeStateRunning:
+			err = client.StartNotebookRuntime(ctx, name)
+		case gcp.RuntimeStateStopped:
+			err = client.StopNotebookRuntime(ctx, name)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return client.GetNotebookRuntime(name)
+}
+
+// refresh copies the server-side state of the runtime into the model
+func (m *notebookRuntimeModel) refresh(runtime *gcp.NotebookRuntime) {
+
+	m.RuntimeState = types.StringPointerValue(runtime.RuntimeState)
+	m.HealthState = types.StringPointerValue(runtime.HealthState)
+	m.ProxyUri = types.StringPointerValue(runtime.ProxyUri)
+	m.ExpirationTime = types.StringPointerValue(runtime.ExpirationTime)
+}
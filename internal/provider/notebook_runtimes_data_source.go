@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &notebookRuntimesDataSource{}
+	_ datasource.DataSourceWithConfigure = &notebookRuntimesDataSource{}
+)
+
+// just making alias to not get confused
+type notebookRuntimesDataSource gcpNotebookClient
+
+func NewNotebookRuntimesDataSource() datasource.DataSource {
+	return &notebookRuntimesDataSource{}
+}
+
+// Configure implements datasource.DataSourceWithConfigure.
+func (n *notebookRuntimesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got %T", req.ProviderData),
+		)
+		return
+	}
+	n.provider = data
+}
+
+// Metadata implements datasource.DataSource.
+func (n *notebookRuntimesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_runtimes"
+}
+
+// Read implements datasource.DataSource.
+func (n *notebookRuntimesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read (notebook_runtimes_data_source) *********")
+
+	var state notebookRuntimesDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+	state.Project = types.StringValue(project)
+	state.Location = types.StringValue(location)
+
+	runtimes, err := n.provider.notebookClient(project, location).GetNotebookRuntimes(state.Filter.ValueString())
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read GCP Notebook Runtimes",
+			err.Error(),
+		)
+		return
+	}
+
+	state.Runtimes = []notebookRuntimeItemModel{}
+
+	for _, runtime := range runtimes.NotebookRuntimes {
+
+		template := ""
+		if runtime.NotebookRuntimeTemplateRef != nil {
+			template = stringOrEmpty(runtime.NotebookRuntimeTemplateRef.NotebookRuntimeTemplate)
+		}
+
+		// templates are matched on their id as the reference may use the project number
+		if !state.NotebookRuntimeTemplate.IsNull() && path.Base(template) != path.Base(state.NotebookRuntimeTemplate.ValueString()) {
+			continue
+		}
+
+		if !state.RuntimeUser.IsNull() && !strings.EqualFold(stringOrEmpty(runtime.RuntimeUser), state.RuntimeUser.ValueString()) {
+			continue
+		}
+
+		if !state.RuntimeState.IsNull() && stringOrEmpty(runtime.RuntimeState) != state.RuntimeState.ValueString() {
+			continue
+		}
+
+		runtimeState := notebookRuntimeItemModel{
+			Name:                    types.StringPointerValue(runtime.Name),
+			DisplayName:             types.StringPointerValue(runtime.DisplayName),
+			Description:             types.StringPointerValue(runtime.Description),
+			RuntimeUser:             types.StringPointerValue(runtime.RuntimeUser),
+			NotebookRuntimeTemplate: types.StringValue(template),
+			RuntimeState:            types.StringPointerValue(runtime.RuntimeState),
+			HealthState:             types.StringPointerValue(runtime.HealthState),
+			ProxyUri:                types.StringPointerValue(runtime.ProxyUri),
+			ServiceAccount:          types.StringPointerValue(runtime.ServiceAccount),
+			NotebookRuntimeType:     types.StringPointerValue(runtime.NotebookRuntimeType),
+			CreateTime:              types.StringPointerValue(runtime.CreateTime),
+			UpdateTime:              types.StringPointerValue(runtime.UpdateTime),
+			ExpirationTime:          types.StringPointerValue(runtime.ExpirationTime),
+		}
+
+		if runtime.MachineSpec != nil {
+			runtimeState.MachineSpec = &notebookMachineSpecModel{
+				MachineType:      types.StringPointerValue(runtime.MachineSpec.MachineType),
+				AcceleratorType:  types.StringPointerValue(runtime.MachineSpec.AcceleratorType),
+				AcceleratorCount: types.Int64PointerValue(runtime.MachineSpec.AcceleratorCount),
+			}
+		}
+
+		if runtime.IdleShutdownConfig != nil {
+			runtimeState.IdleShutdownConfig = &notebookIdleShutdownConfigModel{
+				IdleTimeout:          types.StringPointerValue(runtime.IdleShutdownConfig.IdleTimeout),
+				IdleShutdownDisabled: types.BoolValue(runtime.IdleShutdownConfig.IdleShutdownDisabled != nil && *runtime.IdleShutdownConfig.IdleShutdownDisabled),
+			}
+		}
+
+		var labels map[string]string
+		if runtime.Labels != nil {
+			labels = *runtime.Labels
+		}
+		runtimeState.Labels, diags = labelsMapValue(ctx, labels)
+		resp.Diagnostics.Append(diags...)
+
+		state.Runtimes = append(state.Runtimes, runtimeState)
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Schema implements datasource.DataSource.
+func (n *notebookRuntimesDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema (notebook_runtimes_data_source) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Colab Enterprise runtimes in a location",
+		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The project to list runtimes in, defaults to the provider project",
+			},
+			"location": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The location to list runtimes in, defaults to the provider location",
+			},
+			"filter": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A server-side filter expression using the API filter syntax, e.g. `healthState=HEALTHY`",
+			},
+			"notebook_runtime_template": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return runtimes created from this template, either its full name or id",
+			},
+			"runtime_user": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return runtimes assigned to this user",
+			},
+			"runtime_state": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return runtimes in this state, e.g. `RUNNING` or `STOPPED`",
+			},
+			"runtimes": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"display_name": schema.StringAttribute{
+							Computed: true,
+						},
+						"description": schema.StringAttribute{
+							Computed: true,
+						},
+						"runtime_user": schema.StringAttribute{
+							Computed: true,
+						},
+						"notebook_runtime_template": schema.StringAttribute{
+							Computed: true,
+						},
+						"runtime_state": schema.StringAttribute{
+							Computed: true,
+						},
+						"health_state": schema.StringAttribute{
+							Computed: true,
+						},
+						"proxy_uri": schema.StringAttribute{
+							Computed: true,
+						},
+						"service_account": schema.StringAttribute{
+							Computed: true,
+						},
+						"notebook_runtime_type": schema.StringAttribute{
+							Computed: true,
+						},
+						"create_time": schema.StringAttribute{
+							Computed: true,
+						},
+						"update_time": schema.StringAttribute{
+							Computed: true,
+						},
+						"expiration_time": schema.StringAttribute{
+							Computed: true,
+						},
+						"machine_spec": schema.SingleNestedAttribute{
+							Computed: true,
+							Attributes: map[string]schema.Attribute{
+								"machine_type": schema.StringAttribute{
+									Computed: true,
+								},
+								"accelerator_type": schema.StringAttribute{
+									Computed: true,
+								},
+								"accelerator_count": schema.Int64Attribute{
+									Computed: true,
+								},
+							},
+						},
+						"idle_shutdown_config": schema.SingleNestedAttribute{
+							Computed: true,
+							Attributes: map[string]schema.Attribute{
+								"idle_timeout": schema.StringAttribute{
+									Computed: true,
+								},
+								"idle_shutdown_disabled": schema.BoolAttribute{
+									Computed: true,
+								},
+							},
+						},
+						"labels": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,531 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &notebookScheduleResource{}
+	_ resource.ResourceWithConfigure      = &notebookScheduleResource{}
+	_ resource.ResourceWithValidateConfig = &notebookScheduleResource{}
+)
+
+// just making alias to not get confused
+type notebookScheduleResource gcpNotebookClient
+
+func NewNotebookScheduleResource() resource.Resource {
+	return &notebookScheduleResource{}
+}
+
+func (n *notebookScheduleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	n.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (n *notebookScheduleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notebook_schedule"
+}
+
+func (n *notebookScheduleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+
+	var data notebookScheduleModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.MaxConcurrentRunCount.IsNull() && !data.MaxConcurrentRunCount.IsUnknown() && data.MaxConcurrentRunCount.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			tfpath.Root("max_concurrent_run_count"),
+			"max_concurrent_run_count must be at least 1",
+			fmt.Sprintf("Expected max_concurrent_run_count to be at least 1, got %d", data.MaxConcurrentRunCount.ValueInt64()),
+		)
+	}
+
+	if !data.MaxRunCount.IsNull() && !data.MaxRunCount.IsUnknown() && data.MaxRunCount.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			tfpath.Root("max_run_count"),
+			"max_run_count must be at least 1",
+			fmt.Sprintf("Expected max_run_count to be at least 1, got %d", data.MaxRunCount.ValueInt64()),
+		)
+	}
+
+	for attribute, value := range map[string]types.String{"start_time": data.StartTime, "end_time": data.EndTime} {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				tfpath.Root(attribute),
+				attribute+" must be an RFC3339 timestamp",
+				fmt.Sprintf("Expected %s to be an RFC3339 timestamp, e.g. 2024-01-01T00:00:00Z: %s", attribute, err.Error()),
+			)
+		}
+	}
+
+	if data.NotebookExecutionJob != nil {
+		resp.Diagnostics.Append(validateExecutionJobSpec(tfpath.Root("notebook_execution_job"), *data.NotebookExecutionJob)...)
+	}
+}
+
+// Schema implements resource.Resource.
+func (n *notebookScheduleResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(notebook_schedule_resource) *********")
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs a notebook execution job on a cron schedule",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The project to create the schedule in, defaults to the provider project",
+			},
+			"location": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The location to create the schedule in, defaults to the provider location",
+			},
+			"display_name": schema.StringAttribute{
+				Required: true,
+			},
+			"cron": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "A cron expression, optionally prefixed with a time zone, e.g. `TZ=Australia/Melbourne 0 2 * * *`",
+			},
+			"start_time": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "When the schedule starts (RFC3339), defaults to the time it is created",
+			},
+			"end_time": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "When the schedule completes (RFC3339)",
+			},
+			"max_run_count": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "The schedule completes after this many runs",
+			},
+			"max_concurrent_run_count": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "The maximum number of runs in flight at the same time",
+			},
+			"allow_queueing": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Queue runs when `max_concurrent_run_count` is reached rather than skipping them",
+			},
+			"paused": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Pause (`true`) or resume (`false`) the schedule in place, missed runs are not caught up",
+			},
+			"notebook_execution_job": schema.SingleNestedAttribute{
+				Required:            true,
+				MarkdownDescription: "The job to run, with the same arguments as `daw_notebook_execution_job`",
+				Attributes:          executionJobSpecAttributes(false),
+			},
+			"state": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "One of `ACTIVE`, `PAUSED` or `COMPLETED`",
+			},
+			"started_run_count": schema.Int64Attribute{
+				Computed: true,
+			},
+			"next_run_time": schema.StringAttribute{
+				Computed: true,
+			},
+			"create_time": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"update_time": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Create implements resource.Resource.
+func (n *notebookScheduleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(notebook_schedule_resource) *********")
+
+	var plan notebookScheduleModel
+	diags := req.Plan.Get(ctx, &plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	schedule, diags := plan.toSchedule(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+	plan.Project = types.StringValue(project)
+	plan.Location = types.StringValue(location)
+
+	client := n.provider.notebookClient(project, location)
+
+	created, err := client.CreateSchedule(schedule)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating schedule",
+			"Could not create schedule, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// save what we have so a failure to pause doesn't orphan the schedule
+	paused := plan.Paused
+	plan.Name = types.StringPointerValue(created.Name)
+	plan.Paused = types.BoolValue(false)
+	plan.refresh(created)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if paused.ValueBool() {
+		created, err = n.applyPaused(client, plan.Name.ValueString(), true)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error pausing schedule",
+				"Could not pause schedule, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		plan.Paused = paused
+		plan.refresh(created)
+	}
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read implements resource.Resource.
+func (n *notebookScheduleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(notebook_schedule_resource) *********")
+
+	var state notebookScheduleModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
+	schedule, err := n.provider.notebookClient(project, location).GetSchedule(state.Name.ValueString())
+
+	if gcp.IsNotFound(err) {
+		tflog.Warn(ctx, "Schedule no longer exists, removing from state", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading GCP Schedule",
+			"Could not read schedule with name "+state.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Project = types.StringValue(project)
+	state.Location = types.StringValue(location)
+	state.DisplayName = types.StringPointerValue(schedule.DisplayName)
+	state.Cron = types.StringPointerValue(schedule.Cron)
+	state.StartTime = refreshTime(state.StartTime, schedule.StartTime)
+	state.EndTime = refreshTime(state.EndTime, schedule.EndTime)
+	state.MaxRunCount = types.Int64PointerValue(schedule.MaxRunCount)
+	state.MaxConcurrentRunCount = types.Int64PointerValue(schedule.MaxConcurrentRunCount)
+	state.AllowQueueing = types.BoolValue(schedule.AllowQueueing != nil && *schedule.AllowQueueing)
+
+	if schedule.CreateNotebookExecutionJobRequest != nil && schedule.CreateNotebookExecutionJobRequest.NotebookExecutionJob != nil {
+		job, diags := executionJobSpecFromAPI(ctx, schedule.CreateNotebookExecutionJobRequest.NotebookExecutionJob)
+		resp.Diagnostics.Append(diags...)
+		state.NotebookExecutionJob = &job
+	}
+
+	// surface a schedule paused or resumed outside of Terraform as a change
+	// to paused, a completed schedule keeps its last value
+	switch stringOrEmpty(schedule.State) {
+	case gcp.ScheduleStateActive:
+		state.Paused = types.BoolValue(false)
+	case gcp.ScheduleStatePaused:
+		state.Paused = types.BoolValue(true)
+	}
+
+	state.refresh(schedule)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update implements resource.Resource.
+func (n *notebookScheduleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	tflog.Debug(ctx, "********* In Update(notebook_schedule_resource) *********")
+
+	var plan, state notebookScheduleModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, diags := plan.toSchedule(ctx)
+	resp.Diagnostics.Append(diags...)
+	current, diags := state.toSchedule(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(plan.Project, plan.Location)
+	client := n.provider.notebookClient(project, location)
+
+	planned.Name = state.Name.ValueStringPointer()
+
+	if updateMask := scheduleUpdateMask(planned, current); len(updateMask) > 0 {
+
+		schedule, err := client.UpdateSchedule(planned, updateMask)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating schedule",
+				"Could not update schedule, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		plan.refresh(schedule)
+	}
+
+	if plan.Paused.ValueBool() != state.Paused.ValueBool() {
+
+		schedule, err := n.applyPaused(client, state.Name.ValueString(), plan.Paused.ValueBool())
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating schedule",
+				"Could not pause or resume schedule, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		plan.refresh(schedule)
+	}
+
+	// nothing changed server side, keep the computed values we had
+	if plan.State.IsUnknown() {
+		plan.State = state.State
+		plan.StartedRunCount = state.StartedRunCount
+		plan.NextRunTime = state.NextRunTime
+		plan.UpdateTime = state.UpdateTime
+	}
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete implements resource.Resource.
+func (n *notebookScheduleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(notebook_schedule_resource) *********")
+
+	var state notebookScheduleModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := n.provider.projectLocation(state.Project, state.Location)
+
+	err := n.provider.notebookClient(project, location).DeleteSchedule(ctx, state.Name.ValueString())
+
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting schedule",
+			"Could not delete schedule, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// applyPaused pauses or resumes the schedule, returning its new state
+func (n *notebookScheduleResource) applyPaused(client *gcp.NotebookClient, name string, paused bool) (*gcp.Schedule, error) {
+
+	var err error
+
+	if paused {
+		err = client.PauseSchedule(name)
+	} else {
+		err = client.ResumeSchedule(name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return client.GetSchedule(name)
+}
+
+// refresh copies the server-side state of the schedule into the model
+func (m *notebookScheduleModel) refresh(schedule *gcp.Schedule) {
+
+	m.State = types.StringPointerValue(schedule.State)
+	m.StartedRunCount = types.Int64Value(0)
+	m.NextRunTime = types.StringPointerValue(schedule.NextRunTime)
+	m.CreateTime = types.StringPointerValue(schedule.CreateTime)
+	m.UpdateTime = types.StringPointerValue(schedule.UpdateTime)
+	m.StartTime = refreshTime(m.StartTime, schedule.StartTime)
+
+	if schedule.StartedRunCount != nil {
+		m.StartedRunCount = types.Int64Value(*schedule.StartedRunCount)
+	}
+}
+
+// toSchedule converts the arguments into the API representation
+func (m notebookScheduleModel) toSchedule(ctx context.Context) (*gcp.Schedule, diag.Diagnostics) {
+
+	var diags diag.Diagnostics
+
+	schedule := &gcp.Schedule{
+		DisplayName:           m.DisplayName.ValueStringPointer(),
+		Cron:                  m.Cron.ValueStringPointer(),
+		EndTime:               m.EndTime.ValueStringPointer(),
+		MaxRunCount:           m.MaxRunCount.ValueInt64Pointer(),
+		MaxConcurrentRunCount: m.MaxConcurrentRunCount.ValueInt64Pointer(),
+		AllowQueueing:         m.AllowQueueing.ValueBoolPointer(),
+	}
+
+	if !m.StartTime.IsUnknown() {
+		schedule.StartTime = m.StartTime.ValueStringPointer()
+	}
+
+	if m.NotebookExecutionJob != nil {
+		var job *gcp.NotebookExecutionJob
+		job, diags = m.NotebookExecutionJob.toExecutionJob(ctx)
+		schedule.CreateNotebookExecutionJobRequest = &gcp.CreateNotebookExecutionJobRequest{
+			NotebookExecutionJob: job,
+		}
+	}
+
+	return schedule, diags
+}
+
+// scheduleUpdateMask lists the fields that differ between the planned and
+// current schedule
+func scheduleUpdateMask(planned *gcp.Schedule, current *gcp.Schedule) []string {
+
+	var updateMask []string
+
+	fields := []struct {
+		mask             string
+		planned, current interface{}
+	}{
+		{"display_name", planned.DisplayName, current.DisplayName},
+		{"cron", planned.Cron, current.Cron},
+		{"start_time", planned.StartTime, current.StartTime},
+		{"end_time", planned.EndTime, current.EndTime},
+		{"max_run_count", planned.MaxRunCount, current.MaxRunCount},
+		{"max_concurrent_run_count", planned.MaxConcurrentRunCount, current.MaxConcurrentRunCount},
+		{"allow_queueing", planned.AllowQueueing, current.AllowQueueing},
+		{"create_notebook_execution_job_request", planned.CreateNotebookExecutionJobRequest, current.CreateNotebookExecutionJobRequest},
+	}
+
+	for _, field := range fields {
+		if !reflect.DeepEqual(field.planned, field.current) {
+			updateMask = append(updateMask, field.mask)
+		}
+	}
+	return updateMask
+}
+
+// refreshTime keeps the prior timestamp when the API returns the same
+// instant in a different format (e.g. with fractional seconds)
+func refreshTime(prior types.String, actual *string) types.String {
+
+	if actual == nil {
+		return types.StringNull()
+	}
+
+	if !prior.IsNull() && !prior.IsUnknown() {
+		priorTime, errPrior := time.Parse(time.RFC3339, prior.ValueString())
+		actualTime, errActual := time.Parse(time.RFC3339, *actual)
+
+		if errPrior == nil && errActual == nil && priorTime.Equal(actualTime) {
+			return prior
+		}
+	}
+	return types.StringValue(*actual)
+}
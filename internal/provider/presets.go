@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// presetModel is a named template shape defined on the provider, e.g. "t4_gpu"
+type presetModel struct {
+	Name                   types.String                         `tfsdk:"name"`
+	MachineSpec            *notebookMachineSpecModel            `tfsdk:"machine_spec"`
+	DataPersistentDiskSpec *notebookDataPersistentDiskSpecModel `tfsdk:"data_persistent_disk_spec"`
+	IdleShutdownConfig     *notebookIdleShutdownConfigModel     `tfsdk:"idle_shutdown_config"`
+}
+
+// the daw_notebook attributes a preset fills, with the value of the preset
+// holding them
+var presetAttributes = map[string]func(preset presetModel) any{
+	"machine_spec":              func(preset presetModel) any { return preset.MachineSpec },
+	"data_persistent_disk_spec": func(preset presetModel) any { return preset.DataPersistentDiskSpec },
+	"idle_shutdown_config":      func(preset presetModel) any { return preset.IdleShutdownConfig },
+}
+
+// values of preset attributes set neither on the resource, by the preset nor
+// in state, i.e. when creating
+var presetAttributeDefaults = map[string]map[string]attr.Value{
+	"idle_shutdown_config": {
+		"idle_shutdown_disabled": types.BoolValue(false),
+	},
+}
+
+// newPresets indexes the presets by name, their accelerators have to fit the
+// compatibility table like those of any template
+func newPresets(models []presetModel, table map[string]acceleratorCompatibility) (map[string]presetModel, diag.Diagnostics) {
+
+	var diags diag.Diagnostics
+
+	presets := make(map[string]presetModel, len(models))
+
+	for i, preset := range models {
+
+		name := preset.Name.ValueString()
+
+		if _, ok := presets[name]; ok {
+			diags.AddAttributeError(
+				path.Root("preset").AtListIndex(i).AtName("name"),
+				"Duplicate preset",
+				fmt.Sprintf("A preset named %q is already defined", name),
+			)
+			continue
+		}
+
+		if preset.MachineSpec != nil {
+			for _, d := range validateMachineSpec(table, true, *preset.MachineSpec) {
+				diags.AddAttributeError(path.Root("preset").AtListIndex(i).AtName("machine_spec"), d.Summary(), d.Detail())
+			}
+		}
+
+		presets[name] = preset
+	}
+	return presets, diags
+}
+
+// presetNames lists the presets for error messages
+func presetNames(presets map[string]presetModel) string {
+
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return "none are defined"
+	}
+	return strings.Join(names, ", ")
+}
+
+// resolvePresets plans machine_spec, data_persistent_disk_spec and
+// idle_shutdown_config. Each attribute takes the value configured on the
+// resource, then the one from the preset and otherwise keeps its current
+// value. A resolved spec differing from state replaces the template.
+func (d *providerData) resolvePresets(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+
+	var presetName types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("preset"), &presetName)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if presetName.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("preset"),
+			"Unknown preset",
+			"The preset has to be known when planning, it can't depend on values known only after apply",
+		)
+		return
+	}
+
+	// unknown names are reported by ValidateConfig
+	preset, hasPreset := d.presets[presetName.ValueString()]
+
+	for attribute, presetValue := range presetAttributes {
+
+		at := path.Root(attribute)
+
+		var config, state, plan types.Object
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, at, &config)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, at, &state)...)
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, at, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		attributeTypes := plan.AttributeTypes(ctx)
+
+		fromPreset := types.ObjectNull(attributeTypes)
+		if hasPreset {
+			var diags diag.Diagnostics
+			fromPreset, diags = types.ObjectValueFrom(ctx, attributeTypes, presetValue(preset))
+			resp.Diagnostics.Append(diags...)
+		}
+
+		resolved, diags := resolvePresetObject(attributeTypes, config, fromPreset, state, presetAttributeDefaults[attribute])
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, at, resolved)...)
+
+		if !req.State.Raw.IsNull() && !resolved.Equal(state) {
+			resp.RequiresReplace = append(resp.RequiresReplace, at)
+		}
+	}
+}
+
+// resolvePresetObject merges the object an attribute at a time, see resolvePresets
+func resolvePresetObject(attributeTypes map[string]attr.Type, config types.Object, preset types.Object, state types.Object, defaults map[string]attr.Value) (types.Object, diag.Diagnostics) {
+
+	if config.IsUnknown() {
+		return config, nil
+	}
+
+	if config.IsNull() && preset.IsNull() {
+		if state.IsNull() {
+			return types.ObjectNull(attributeTypes), nil
+		}
+		return state, nil
+	}
+
+	values := make(map[string]attr.Value, len(attributeTypes))
+
+	for name, attributeType := range attributeTypes {
+
+		value, ok := defaults[name]
+		if !ok {
+			value = nullValue(attributeType)
+		}
+
+		if !state.IsNull() && !state.IsUnknown() {
+			value = state.Attributes()[name]
+		}
+		if v := preset.Attributes()[name]; !preset.IsNull() && !v.IsNull() {
+			value = v
+		}
+		if v := config.Attributes()[name]; !config.IsNull() && !v.IsNull() {
+			value = v
+		}
+
+		values[name] = value
+	}
+
+	return types.ObjectValue(attributeTypes, values)
+}
+
+// nullValue is the null value of the primitive types used by presets
+func nullValue(attributeType attr.Type) attr.Value {
+
+	switch attributeType {
+	case types.BoolType:
+		return types.BoolNull()
+	case types.Int64Type:
+		return types.Int64Null()
+	default:
+		return types.StringNull()
+	}
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestResolvePresetObject(t *testing.T) {
+
+	attributeTypes := map[string]attr.Type{
+		"idle_timeout":           types.StringType,
+		"idle_shutdown_disabled": types.BoolType,
+	}
+
+	object := func(idleTimeout attr.Value, idleShutdownDisabled attr.Value) types.Object {
+		return types.ObjectValueMust(attributeTypes, map[string]attr.Value{
+			"idle_timeout":           idleTimeout,
+			"idle_shutdown_disabled": idleShutdownDisabled,
+		})
+	}
+
+	null := types.ObjectNull(attributeTypes)
+	defaults := map[string]attr.Value{"idle_shutdown_disabled": types.BoolValue(false)}
+
+	tests := []struct {
+		name                  string
+		config, preset, state types.Object
+		want                  types.Object
+	}{
+		{
+			name:   "nothing set",
+			config: null, preset: null, state: null,
+			want: null,
+		},
+		{
+			name:   "kept from state when neither configured nor in the preset",
+			config: null, preset: null, state: object(types.StringValue("3600s"), types.BoolValue(false)),
+			want: object(types.StringValue("3600s"), types.BoolValue(false)),
+		},
+		{
+			name:   "preset fills the object, defaults the rest",
+			config: null, preset: object(types.StringValue("1800s"), types.BoolNull()), state: null,
+			want: object(types.StringValue("1800s"), types.BoolValue(false)),
+		},
+		{
+			name:   "configured attributes win over the preset",
+			config: object(types.StringValue("7200s"), types.BoolNull()), preset: object(types.StringValue("1800s"), types.BoolNull()), state: null,
+			want: object(types.StringValue("7200s"), types.BoolValue(false)),
+		},
+		{
+			name:   "attributes missing from config and preset keep their state",
+			config: object(types.StringNull(), types.BoolNull()), preset: object(types.StringValue("1800s"), types.BoolNull()), state: object(types.StringValue("3600s"), types.BoolValue(true)),
+			want: object(types.StringValue("1800s"), types.BoolValue(true)),
+		},
+		{
+			name:   "unknown config stays unknown",
+			config: types.ObjectUnknown(attributeTypes), preset: object(types.StringValue("1800s"), types.BoolNull()), state: null,
+			want: types.ObjectUnknown(attributeTypes),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			got, diags := resolvePresetObject(attributeTypes, tt.config, tt.preset, tt.state, defaults)
+
+			if diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -5,11 +5,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+	"github.com/mpstella/terraform-provider-daw/internal/pricing"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -19,7 +23,10 @@ import (
 )
 
 // Ensure notebookProvider satisfies various provider interfaces.
-var _ provider.Provider = &notebookProvider{}
+var (
+	_ provider.Provider              = &notebookProvider{}
+	_ provider.ProviderWithFunctions = &notebookProvider{}
+)
 
 // notebookProvider defines the provider implementation.
 type notebookProvider struct {
@@ -30,8 +37,31 @@ type notebookProvider struct {
 }
 
 type notebookProviderModel struct {
-	Project  types.String `tfsdk:"project"`
-	Location types.String `tfsdk:"location"`
+	Project                            types.String                             `tfsdk:"project"`
+	Location                           types.String                             `tfsdk:"location"`
+	Credentials                        types.String                             `tfsdk:"credentials"`
+	AccessToken                        types.String                             `tfsdk:"access_token"`
+	ImpersonateServiceAccount          types.String                             `tfsdk:"impersonate_service_account"`
+	ImpersonateServiceAccountDelegates types.List                               `tfsdk:"impersonate_service_account_delegates"`
+	BillingProject                     types.String                             `tfsdk:"billing_project"`
+	UserProjectOverride                types.Bool                               `tfsdk:"user_project_override"`
+	UserAgentSuffix                    types.String                             `tfsdk:"user_agent_suffix"`
+	VertexAICustomEndpoint             types.String                             `tfsdk:"vertex_ai_custom_endpoint"`
+	NotebooksCustomEndpoint            types.String                             `tfsdk:"notebooks_custom_endpoint"`
+	DataformCustomEndpoint             types.String                             `tfsdk:"dataform_custom_endpoint"`
+	ComputeCustomEndpoint              types.String                             `tfsdk:"compute_custom_endpoint"`
+	MaxRetries                         types.Int64                              `tfsdk:"max_retries"`
+	DefaultLabels                      types.Map                                `tfsdk:"default_labels"`
+	IgnoreLabels                       *ignoreLabelsModel                       `tfsdk:"ignore_labels"`
+	AcceleratorCompatibility           map[string]acceleratorCompatibilityModel `tfsdk:"accelerator_compatibility"`
+	Guardrails                         *guardrailsModel                         `tfsdk:"guardrails"`
+	PriceFile                          types.String                             `tfsdk:"price_file"`
+	Presets                            []presetModel                            `tfsdk:"preset"`
+}
+
+type ignoreLabelsModel struct {
+	Keys        types.Set `tfsdk:"keys"`
+	KeyPrefixes types.Set `tfsdk:"key_prefixes"`
 }
 
 func New(version string) func() provider.Provider {
@@ -56,6 +86,190 @@ func (p *notebookProvider) Schema(ctx context.Context, req provider.SchemaReques
 			"location": schema.StringAttribute{
 				Optional: true,
 			},
+			"credentials": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Path to or contents of a service account key file, defaults to `GOOGLE_CREDENTIALS` then the application default credentials",
+			},
+			"access_token": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "A temporary OAuth 2.0 access token, defaults to `GOOGLE_OAUTH_ACCESS_TOKEN`. Takes precedence over `credentials`",
+			},
+			"impersonate_service_account": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The email of a service account to impersonate, defaults to `GOOGLE_IMPERSONATE_SERVICE_ACCOUNT`",
+			},
+			"impersonate_service_account_delegates": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The delegation chain used to impersonate `impersonate_service_account`",
+			},
+			"billing_project": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The project charged for quota and billing when `user_project_override` is set, defaults to `GOOGLE_BILLING_PROJECT`",
+			},
+			"user_project_override": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Send the `x-goog-user-project` header with every request, charging `billing_project` (or the resource project) instead of the credentials' project. Defaults to `USER_PROJECT_OVERRIDE`",
+			},
+			"user_agent_suffix": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Appended to the `User-Agent` sent with every request, defaults to `GOOGLE_TERRAFORM_USERAGENT_EXTENSION`",
+			},
+			"vertex_ai_custom_endpoint": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Replaces the Vertex AI endpoint, e.g. `https://{{location}}-aiplatform.googleapis.com/v1beta1`. Defaults to `GOOGLE_VERTEX_AI_CUSTOM_ENDPOINT`",
+			},
+			"notebooks_custom_endpoint": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Replaces the Notebooks (Workbench) endpoint, e.g. `https://notebooks.googleapis.com/v2`. Defaults to `GOOGLE_NOTEBOOKS_CUSTOM_ENDPOINT`",
+			},
+			"dataform_custom_endpoint": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Replaces the Dataform endpoint, e.g. `https://dataform.googleapis.com/v1beta1`. Defaults to `GOOGLE_DATAFORM_CUSTOM_ENDPOINT`",
+			},
+			"compute_custom_endpoint": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Replaces the Compute Engine endpoint, e.g. `https://compute.googleapis.com/compute/v1`. Defaults to `GOOGLE_COMPUTE_CUSTOM_ENDPOINT`",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: fmt.Sprintf("How often a request failing with a transient error (429, 502, 503 or 504) is retried, requests creating something only on 429. Defaults to %d", gcp.DefaultMaxRetries),
+			},
+			"default_labels": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Labels merged into the `labels` of every `daw_notebook`, values set on the resource win",
+			},
+			"ignore_labels": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Labels applied by GCP or other tooling that are left out of state and never sent to the API, configuring one in `labels` is an error",
+				Attributes: map[string]schema.Attribute{
+					"keys": schema.SetAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "Exact label keys to ignore",
+					},
+					"key_prefixes": schema.SetAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "Label key prefixes to ignore, e.g. `goog-`",
+					},
+				},
+			},
+			"accelerator_compatibility": schema.MapNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Overrides the built-in table of which machine families an accelerator type (the map key, e.g. `NVIDIA_TESLA_T4`) attaches to and how many of it are allowed. `terraform validate` only knows the built-in table",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"machine_families": schema.ListAttribute{
+							Required:            true,
+							ElementType:         types.StringType,
+							MarkdownDescription: "The machine families the accelerator attaches to, e.g. `n1`",
+						},
+						"counts": schema.ListAttribute{
+							Required:            true,
+							ElementType:         types.Int64Type,
+							MarkdownDescription: "The allowed values of `accelerator_count`",
+						},
+					},
+				},
+			},
+			"price_file": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A JSON price catalogue laid over the built-in one used for `estimated_hourly_cost`, defaults to `DAW_PRICE_FILE`",
+			},
+			"guardrails": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Organisation policies every `daw_notebook` must satisfy, a violation fails the plan. They are enforced when planning only, `terraform validate` doesn't check them",
+				Attributes: map[string]schema.Attribute{
+					"require_kms_key": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Templates must set `kms_key_name`",
+					},
+					"allowed_machine_types": schema.ListAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "The machine types templates can use, patterns such as `n1-standard-*` are allowed",
+					},
+					"max_accelerator_count": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: "The maximum `accelerator_count` of a template",
+					},
+					"allowed_accelerator_types": schema.ListAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "The accelerator types templates can use, patterns are allowed",
+					},
+					"forbid_internet_access": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Templates must not set `enable_internet_access`",
+					},
+					"required_labels": schema.ListAttribute{
+						Optional:            true,
+						ElementType:         types.StringType,
+						MarkdownDescription: "Label keys every template must have, `default_labels` count",
+					},
+					"max_disk_size_gb": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: "The maximum data disk size of a template",
+					},
+					"require_idle_shutdown": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Templates must not set `idle_shutdown_disabled`",
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"preset": schema.ListNestedBlock{
+				MarkdownDescription: "A named template shape, a `daw_notebook` with this `preset` takes the specs it doesn't set from it",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "The name templates refer to the preset by, e.g. `t4_gpu`",
+						},
+						"machine_spec": schema.SingleNestedAttribute{
+							Optional: true,
+							Attributes: map[string]schema.Attribute{
+								"machine_type": schema.StringAttribute{
+									Optional: true,
+								},
+								"accelerator_type": schema.StringAttribute{
+									Optional: true,
+								},
+								"accelerator_count": schema.Int64Attribute{
+									Optional: true,
+								},
+							},
+						},
+						"data_persistent_disk_spec": schema.SingleNestedAttribute{
+							Optional: true,
+							Attributes: map[string]schema.Attribute{
+								"disk_type": schema.StringAttribute{
+									Optional: true,
+								},
+								"disk_size_gb": schema.StringAttribute{
+									Optional: true,
+								},
+							},
+						},
+						"idle_shutdown_config": schema.SingleNestedAttribute{
+							Optional: true,
+							Attributes: map[string]schema.Attribute{
+								"idle_timeout": schema.StringAttribute{
+									Optional: true,
+								},
+								"idle_shutdown_disabled": schema.BoolAttribute{
+									Optional: true,
+								},
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -85,6 +299,44 @@ func (p *notebookProvider) Configure(ctx context.Context, req provider.Configure
 		)
 	}
 
+	if config.BillingProject.IsUnknown() || config.UserProjectOverride.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown Billing Project",
+			"The provider cannot create the client as there is an unknown configuration value for billing_project or user_project_override",
+		)
+	}
+
+	if config.Credentials.IsUnknown() || config.AccessToken.IsUnknown() || config.ImpersonateServiceAccount.IsUnknown() || config.ImpersonateServiceAccountDelegates.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown Credentials",
+			"The provider cannot create the client as there is an unknown configuration value for credentials, access_token or impersonation",
+		)
+	}
+
+	if config.DefaultLabels.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_labels"),
+			"Unknown Default Labels",
+			"The provider cannot plan labels as there is an unknown configuration value for default_labels",
+		)
+	}
+
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() && config.MaxRetries.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid Max Retries",
+			"max_retries cannot be negative",
+		)
+	}
+
+	if !config.Credentials.IsNull() && !config.AccessToken.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("access_token"),
+			"Conflicting Credentials",
+			"Only one of credentials and access_token can be configured",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -113,6 +365,62 @@ func (p *notebookProvider) Configure(ctx context.Context, req provider.Configure
 		location = "australia-southeast1"
 	}
 
+	// same environment variables as the google provider
+	gcpConfig := gcp.ConfigFromEnv()
+
+	userAgentSuffix := os.Getenv("GOOGLE_TERRAFORM_USERAGENT_EXTENSION")
+	if !config.UserAgentSuffix.IsNull() {
+		userAgentSuffix = config.UserAgentSuffix.ValueString()
+	}
+	gcpConfig.UserAgent = p.userAgent(req.TerraformVersion, userAgentSuffix)
+
+	// explicit configuration wins over any environment variable
+	if !config.Credentials.IsNull() {
+		gcpConfig.Credentials = config.Credentials.ValueString()
+		gcpConfig.AccessToken = ""
+	}
+
+	if !config.AccessToken.IsNull() {
+		gcpConfig.AccessToken = config.AccessToken.ValueString()
+	}
+
+	if !config.ImpersonateServiceAccount.IsNull() {
+		gcpConfig.ImpersonateServiceAccount = config.ImpersonateServiceAccount.ValueString()
+	}
+
+	if !config.BillingProject.IsNull() {
+		gcpConfig.BillingProject = config.BillingProject.ValueString()
+	}
+
+	if !config.UserProjectOverride.IsNull() {
+		gcpConfig.UserProjectOverride = config.UserProjectOverride.ValueBool()
+	}
+
+	if !config.VertexAICustomEndpoint.IsNull() {
+		gcpConfig.VertexAIEndpoint = config.VertexAICustomEndpoint.ValueString()
+	}
+
+	if !config.NotebooksCustomEndpoint.IsNull() {
+		gcpConfig.NotebooksEndpoint = config.NotebooksCustomEndpoint.ValueString()
+	}
+
+	if !config.DataformCustomEndpoint.IsNull() {
+		gcpConfig.DataformEndpoint = config.DataformCustomEndpoint.ValueString()
+	}
+
+	if !config.ComputeCustomEndpoint.IsNull() {
+		gcpConfig.ComputeEndpoint = config.ComputeCustomEndpoint.ValueString()
+	}
+
+	if !config.MaxRetries.IsNull() {
+		maxRetries := int(config.MaxRetries.ValueInt64())
+		gcpConfig.MaxRetries = &maxRetries
+	}
+
+	if !config.ImpersonateServiceAccountDelegates.IsNull() {
+		resp.Diagnostics.Append(config.ImpersonateServiceAccountDelegates.ElementsAs(ctx, &gcpConfig.ImpersonateServiceAccountDelegates, false)...)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -121,7 +429,7 @@ func (p *notebookProvider) Configure(ctx context.Context, req provider.Configure
 	ctx = tflog.SetField(ctx, "gcp_location", location)
 	tflog.Debug(ctx, "Creating GCP client")
 
-	client, err := gcp.NewNotebookClient(project, location)
+	clients, err := gcp.NewClientFactory(gcpConfig)
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -131,8 +439,81 @@ func (p *notebookProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	defaultLabels, diags := labelsFromValue(ctx, config.DefaultLabels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ignore ignoreLabels
+	if config.IgnoreLabels != nil {
+		if !config.IgnoreLabels.Keys.IsNull() {
+			resp.Diagnostics.Append(config.IgnoreLabels.Keys.ElementsAs(ctx, &ignore.keys, false)...)
+		}
+		if !config.IgnoreLabels.KeyPrefixes.IsNull() {
+			resp.Diagnostics.Append(config.IgnoreLabels.KeyPrefixes.ElementsAs(ctx, &ignore.prefixes, false)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	acceleratorCompatibility, diags := acceleratorCompatibilityTable(ctx, config.AcceleratorCompatibility)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	guardrails, diags := newGuardrails(ctx, config.Guardrails)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	presets, diags := newPresets(config.Presets, acceleratorCompatibility)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	priceFile := os.Getenv("DAW_PRICE_FILE")
+	if !config.PriceFile.IsNull() {
+		priceFile = config.PriceFile.ValueString()
+	}
+
+	var prices *pricing.Catalogue
+	if priceFile != "" {
+		prices, err = pricing.Load(priceFile)
+	} else {
+		prices, err = pricing.Default()
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("price_file"),
+			"Unable to Load Price Catalogue",
+			"An unexpected error occurred when loading the price catalogue: "+err.Error(),
+		)
+		return
+	}
+
+	data := &providerData{
+		project:       project,
+		location:      location,
+		defaultLabels: defaultLabels,
+		ignoreLabels:  ignore,
+		clients:       clients,
+		locks:         newMutexKV(),
+
+		acceleratorCompatibility: acceleratorCompatibility,
+		guardrails:               guardrails,
+		prices:                   prices,
+		presets:                  presets,
+		defaultTemplates:         newDefaultTemplates(),
+	}
+
+	resp.DataSourceData = data
+	resp.ResourceData = data
 
 	tflog.Info(ctx, "Configured GCP client", map[string]any{"success": true})
 }
@@ -140,11 +521,47 @@ func (p *notebookProvider) Configure(ctx context.Context, req provider.Configure
 func (p *notebookProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewNotebookResource,
+		NewNotebookRuntimeResource,
+		NewNotebookIamPolicyResource,
+		NewNotebookIamBindingResource,
+		NewNotebookIamMemberResource,
+		NewNotebookExecutionJobResource,
+		NewNotebookScheduleResource,
+		NewWorkbenchInstanceResource,
+		NewNotebookContentResource,
+		NewNotebookDefaultResource,
 	}
 }
 
 func (p *notebookProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewNotebookDataSource,
+		NewNotebookRuntimesDataSource,
+		NewNotebookIamPolicyDataSource,
+		NewMachineTypesDataSource,
+	}
+}
+
+func (p *notebookProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewDecodeTemplateFunction,
+		NewParseTemplateNameFunction,
+		NewTemplateNameFunction,
+		NewDurationSecondsFunction,
+	}
+}
+
+// userAgent identifies the provider release and the Terraform version, e.g.
+// "terraform-provider-daw/0.1.0 terraform/1.8.5 my-pipeline"
+func (p *notebookProvider) userAgent(terraformVersion string, suffix string) string {
+
+	userAgent := fmt.Sprintf("terraform-provider-daw/%s", p.version)
+
+	if terraformVersion != "" {
+		userAgent = fmt.Sprintf("%s terraform/%s", userAgent, terraformVersion)
+	}
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		userAgent = fmt.Sprintf("%s %s", userAgent, suffix)
 	}
+	return userAgent
 }
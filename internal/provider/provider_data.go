@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// projectLocation resolves the project and location of a resource, falling
+// back to the provider configuration when either isn't known
+func (d *providerData) projectLocation(project types.String, location types.String) (string, string) {
+
+	p, l := d.project, d.location
+
+	if !project.IsNull() && !project.IsUnknown() && project.ValueString() != "" {
+		p = project.ValueString()
+	}
+	if !location.IsNull() && !location.IsUnknown() && location.ValueString() != "" {
+		l = location.ValueString()
+	}
+	return p, l
+}
+
+func (d *providerData) notebookClient(project string, location string) *gcp.NotebookClient {
+	return d.clients.NotebookClient(project, location)
+}
+
+func (d *providerData) workbenchClient(project string, zone string) *gcp.WorkbenchClient {
+	return d.clients.WorkbenchClient(project, zone)
+}
+
+func (d *providerData) dataformClient(project string, location string) *gcp.DataformClient {
+	return d.clients.DataformClient(project, location)
+}
+
+func (d *providerData) computeClient(project string) *gcp.ComputeClient {
+	return d.clients.ComputeClient(project)
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// specWarning is a combination of spec values the API is likely to reject,
+// daw_notebook and decode_template warn about the same ones
+type specWarning struct {
+	path    path.Path
+	summary string
+	detail  string
+}
+
+// checkSpecs applies the rules between spec values. Nil specs are skipped,
+// pass a nil machineSpec when a preset may hold the accelerator type.
+func checkSpecs(machineSpec *notebookMachineSpecModel, networkSpec *notebookNetworkSpecModel, idleShutdownConfig *notebookIdleShutdownConfigModel) []specWarning {
+
+	var warnings []specWarning
+
+	if config := idleShutdownConfig; config != nil && !config.IdleTimeout.IsNull() && !config.IdleTimeout.IsUnknown() {
+
+		// check that these values don't conflict
+		if config.IdleShutdownDisabled.ValueBool() {
+			warnings = append(warnings, specWarning{
+				path:    path.Root("idle_shutdown_config").AtName("idle_shutdown_disabled"),
+				summary: "idle_shutdown_disabled can't be set to True and have a value in idle_timeout",
+				detail:  "Expected idle_timeout to be nil if idle_shutdown_disabled is set to True.",
+			})
+		}
+
+		// check the idleTimeout values are correctly applied, [600, 86400]
+		if timeout, err := parseDurationSeconds(config.IdleTimeout.ValueString()); err != nil {
+			warnings = append(warnings, specWarning{
+				path:    path.Root("idle_shutdown_config").AtName("idle_timeout"),
+				summary: "idle_timeout must end in 's' and be a valid integer",
+				detail:  "Expected idle_timeout end in 's' as it is defined in seconds as an integer, " + err.Error(),
+			})
+		} else if seconds := int64(timeout.Seconds()); seconds < 600 || seconds > 86400 {
+			warnings = append(warnings, specWarning{
+				path:    path.Root("idle_shutdown_config").AtName("idle_timeout"),
+				summary: "idle_timeout must end in 's' and be a valid integer between 600 and 86400",
+				detail:  "Expected idle_timeout to be between 600 and 86400",
+			})
+		}
+	}
+
+	// if enable_internet_access is false then both network and subnetwork need to be set
+	if spec := networkSpec; spec != nil && !spec.EnableInternetAccess.IsUnknown() && !spec.EnableInternetAccess.ValueBool() {
+		if spec.Network.IsNull() {
+			warnings = append(warnings, specWarning{
+				path:    path.Root("network_spec").AtName("network"),
+				summary: "network can't be nil if enable_internet_access is false",
+				detail:  "Expected network to be configured",
+			})
+		}
+		if spec.Subnetwork.IsNull() {
+			warnings = append(warnings, specWarning{
+				path:    path.Root("network_spec").AtName("subnetwork"),
+				summary: "subnetwork can't be nil if enable_internet_access is false",
+				detail:  "Expected subnetwork to be configured",
+			})
+		}
+	}
+
+	// check machine type accelerator settings
+	if spec := machineSpec; spec != nil && spec.AcceleratorType.IsNull() && !spec.AcceleratorCount.IsNull() {
+		warnings = append(warnings, specWarning{
+			path:    path.Root("machine_spec").AtName("accelerator_count"),
+			summary: "accelerator_count must be nil if accelerator_type is nil",
+			detail:  "Expected accelerator_count to not be configured",
+		})
+	}
+
+	return warnings
+}
@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+)
+
+// templateClient returns the client for the project and location of the template
+func (d *providerData) templateClient(name string) (*gcp.NotebookClient, error) {
+
+	project, location, _, err := gcp.ParseTemplateName(name)
+
+	if err != nil {
+		return nil, err
+	}
+	return d.notebookClient(project, location), nil
+}
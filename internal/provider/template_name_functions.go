@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ function.Function = &parseTemplateNameFunction{}
+	_ function.Function = &templateNameFunction{}
+)
+
+// parseTemplateNameFunction splits the name of a template without an API call
+type parseTemplateNameFunction struct{}
+
+func NewParseTemplateNameFunction() function.Function {
+	return &parseTemplateNameFunction{}
+}
+
+type templateNamePartsModel struct {
+	Project  types.String `tfsdk:"project"`
+	Location types.String `tfsdk:"location"`
+	Id       types.String `tfsdk:"id"`
+}
+
+func (f *parseTemplateNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_template_name"
+}
+
+func (f *parseTemplateNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Split the name of a runtime template",
+		MarkdownDescription: "Returns the `project`, `location` and `id` of a runtime template name like `projects/{project}/locations/{location}/notebookRuntimeTemplates/{id}`, e.g. the `name` of `daw_notebook`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "The full name of the runtime template",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"project":  types.StringType,
+				"location": types.StringType,
+				"id":       types.StringType,
+			},
+		},
+	}
+}
+
+func (f *parseTemplateNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+
+	var name string
+
+	resp.Error = req.Arguments.Get(ctx, &name)
+	if resp.Error != nil {
+		return
+	}
+
+	project, location, id, err := gcp.ParseTemplateName(name)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, &templateNamePartsModel{
+		Project:  types.StringValue(project),
+		Location: types.StringValue(location),
+		Id:       types.StringValue(id),
+	})
+}
+
+// templateNameFunction builds the name of a template from its parts
+type templateNameFunction struct{}
+
+func NewTemplateNameFunction() function.Function {
+	return &templateNameFunction{}
+}
+
+func (f *templateNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "template_name"
+}
+
+func (f *templateNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Build the name of a runtime template",
+		MarkdownDescription: "Returns `projects/{project}/locations/{location}/notebookRuntimeTemplates/{id}`, the reverse of `parse_template_name`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "project",
+				MarkdownDescription: "The project of the template",
+			},
+			function.StringParameter{
+				Name:                "location",
+				MarkdownDescription: "The location of the template",
+			},
+			function.StringParameter{
+				Name:                "id",
+				MarkdownDescription: "The id of the template, the last part of its name",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *templateNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+
+	var project, location, id string
+
+	resp.Error = req.Arguments.Get(ctx, &project, &location, &id)
+	if resp.Error != nil {
+		return
+	}
+
+	name, err := gcp.TemplateName(project, location, id)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, name)
+}
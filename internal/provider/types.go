@@ -2,26 +2,62 @@ package provider
 
 import (
 	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+	"github.com/mpstella/terraform-provider-daw/internal/pricing"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// providerData is handed to the resources and data sources by Configure
+type providerData struct {
+	project       string
+	location      string
+	defaultLabels map[string]string
+	ignoreLabels  ignoreLabels
+	clients       *gcp.ClientFactory
+
+	// serialises read-modify-write changes, e.g. to IAM policies
+	locks *mutexKV
+
+	// which accelerators attach to which machine types, built-in merged with overrides
+	acceleratorCompatibility map[string]acceleratorCompatibility
+
+	// organisation policies checked when planning templates, nil when not configured
+	guardrails *guardrails
+
+	// the price catalogue cost estimates are made from
+	prices *pricing.Catalogue
+
+	// template shapes daw_notebook can refer to by name
+	presets map[string]presetModel
+
+	// the default template claimed for each location while planning
+	defaultTemplates *defaultTemplates
+}
+
 type gcpNotebookClient struct {
-	client *gcp.NotebookClient
+	provider *providerData
 }
 
 type notebookModel struct {
-	Name                   types.String                        `tfsdk:"name"`
-	DisplayName            types.String                        `tfsdk:"display_name"`
-	Description            types.String                        `tfsdk:"description"`
-	IsDefault              types.Bool                          `tfsdk:"is_default"`
-	EnableSecureBoot       types.Bool                          `tfsdk:"enable_secure_boot"`
-	KmsKeyName             types.String                        `tfsdk:"kms_key_name"`
-	MachineSpec            notebookMachineSpecModel            `tfsdk:"machine_spec"`
-	DataPersistentDiskSpec notebookDataPersistentDiskSpecModel `tfsdk:"data_persistent_disk_spec"`
-	NetworkSpec            notebookNetworkSpecModel            `tfsdk:"network_spec"`
-	IdleShutdownConfig     notebookIdleShutdownConfigModel     `tfsdk:"idle_shutdown_config"`
-	Labels                 types.Map                           `tfsdk:"labels"`
+	Name                   types.String                         `tfsdk:"name"`
+	Project                types.String                         `tfsdk:"project"`
+	Location               types.String                         `tfsdk:"location"`
+	DisplayName            types.String                         `tfsdk:"display_name"`
+	Description            types.String                         `tfsdk:"description"`
+	IsDefault              types.Bool                           `tfsdk:"is_default"`
+	EnableSecureBoot       types.Bool                           `tfsdk:"enable_secure_boot"`
+	KmsKeyName             types.String                         `tfsdk:"kms_key_name"`
+	Preset                 types.String                         `tfsdk:"preset"`
+	DeletionProtection     types.Bool                           `tfsdk:"deletion_protection"`
+	MachineSpec            *notebookMachineSpecModel            `tfsdk:"machine_spec"`
+	DataPersistentDiskSpec *notebookDataPersistentDiskSpecModel `tfsdk:"data_persistent_disk_spec"`
+	NetworkSpec            *notebookNetworkSpecModel            `tfsdk:"network_spec"`
+	IdleShutdownConfig     *notebookIdleShutdownConfigModel     `tfsdk:"idle_shutdown_config"`
+	Labels                 types.Map                            `tfsdk:"labels"`
+	TerraformLabels        types.Map                            `tfsdk:"terraform_labels"`
+	EffectiveLabels        types.Map                            `tfsdk:"effective_labels"`
+	EstimatedHourlyCost    types.Float64                        `tfsdk:"estimated_hourly_cost"`
+	WorstCaseDailyCost     types.Float64                        `tfsdk:"worst_case_daily_cost"`
 }
 
 type notebookMachineSpecModel struct {
@@ -46,6 +82,294 @@ type notebookIdleShutdownConfigModel struct {
 	IdleShutdownDisabled types.Bool   `tfsdk:"idle_shutdown_disabled"`
 }
 
+type notebookEucConfigModel struct {
+	EucDisabled      types.Bool `tfsdk:"euc_disabled"`
+	BypassActasCheck types.Bool `tfsdk:"bypass_actas_check"`
+}
+
+// notebookDataSourceItemModel is a template as returned by the data source,
+// the nested specs are nil when the API doesn't return them
+type notebookDataSourceItemModel struct {
+	Name                   types.String                         `tfsdk:"name"`
+	DisplayName            types.String                         `tfsdk:"display_name"`
+	Description            types.String                         `tfsdk:"description"`
+	IsDefault              types.Bool                           `tfsdk:"is_default"`
+	EnableSecureBoot       types.Bool                           `tfsdk:"enable_secure_boot"`
+	KmsKeyName             types.String                         `tfsdk:"kms_key_name"`
+	ServiceAccount         types.String                         `tfsdk:"service_account"`
+	Etag                   types.String                         `tfsdk:"etag"`
+	CreateTime             types.String                         `tfsdk:"create_time"`
+	UpdateTime             types.String                         `tfsdk:"update_time"`
+	NotebookRuntimeType    types.String                         `tfsdk:"notebook_runtime_type"`
+	MachineSpec            *notebookMachineSpecModel            `tfsdk:"machine_spec"`
+	DataPersistentDiskSpec *notebookDataPersistentDiskSpecModel `tfsdk:"data_persistent_disk_spec"`
+	NetworkSpec            *notebookNetworkSpecModel            `tfsdk:"network_spec"`
+	IdleShutdownConfig     *notebookIdleShutdownConfigModel     `tfsdk:"idle_shutdown_config"`
+	EucConfig              *notebookEucConfigModel              `tfsdk:"euc_config"`
+	Labels                 types.Map                            `tfsdk:"labels"`
+	EstimatedHourlyCost    types.Float64                        `tfsdk:"estimated_hourly_cost"`
+	WorstCaseDailyCost     types.Float64                        `tfsdk:"worst_case_daily_cost"`
+}
+
 type notebookDataSourceModel struct {
-	Notebooks []notebookModel `tfsdk:"notebooks"`
+	Project          types.String                  `tfsdk:"project"`
+	Location         types.String                  `tfsdk:"location"`
+	Filter           types.String                  `tfsdk:"filter"`
+	LabelSelector    types.Map                     `tfsdk:"label_selector"`
+	MachineType      types.String                  `tfsdk:"machine_type"`
+	DisplayNameRegex types.String                  `tfsdk:"display_name_regex"`
+	SortBy           types.String                  `tfsdk:"sort_by"`
+	SortOrder        types.String                  `tfsdk:"sort_order"`
+	Notebooks        []notebookDataSourceItemModel `tfsdk:"notebooks"`
+}
+
+type notebookRuntimeModel struct {
+	Name                    types.String `tfsdk:"name"`
+	Project                 types.String `tfsdk:"project"`
+	Location                types.String `tfsdk:"location"`
+	NotebookRuntimeTemplate types.String `tfsdk:"notebook_runtime_template"`
+	RuntimeUser             types.String `tfsdk:"runtime_user"`
+	DisplayName             types.String `tfsdk:"display_name"`
+	Description             types.String `tfsdk:"description"`
+	DesiredState            types.String `tfsdk:"desired_state"`
+	RuntimeState            types.String `tfsdk:"runtime_state"`
+	HealthState             types.String `tfsdk:"health_state"`
+	ProxyUri                types.String `tfsdk:"proxy_uri"`
+	ExpirationTime          types.String `tfsdk:"expiration_time"`
+}
+
+type notebookRuntimeItemModel struct {
+	Name                    types.String                     `tfsdk:"name"`
+	DisplayName             types.String                     `tfsdk:"display_name"`
+	Description             types.String                     `tfsdk:"description"`
+	RuntimeUser             types.String                     `tfsdk:"runtime_user"`
+	NotebookRuntimeTemplate types.String                     `tfsdk:"notebook_runtime_template"`
+	RuntimeState            types.String                     `tfsdk:"runtime_state"`
+	HealthState             types.String                     `tfsdk:"health_state"`
+	ProxyUri                types.String                     `tfsdk:"proxy_uri"`
+	ServiceAccount          types.String                     `tfsdk:"service_account"`
+	NotebookRuntimeType     types.String                     `tfsdk:"notebook_runtime_type"`
+	CreateTime              types.String                     `tfsdk:"create_time"`
+	UpdateTime              types.String                     `tfsdk:"update_time"`
+	ExpirationTime          types.String                     `tfsdk:"expiration_time"`
+	MachineSpec             *notebookMachineSpecModel        `tfsdk:"machine_spec"`
+	IdleShutdownConfig      *notebookIdleShutdownConfigModel `tfsdk:"idle_shutdown_config"`
+	Labels                  types.Map                        `tfsdk:"labels"`
+}
+
+type notebookRuntimesDataSourceModel struct {
+	Project                 types.String               `tfsdk:"project"`
+	Location                types.String               `tfsdk:"location"`
+	Filter                  types.String               `tfsdk:"filter"`
+	NotebookRuntimeTemplate types.String               `tfsdk:"notebook_runtime_template"`
+	RuntimeUser             types.String               `tfsdk:"runtime_user"`
+	RuntimeState            types.String               `tfsdk:"runtime_state"`
+	Runtimes                []notebookRuntimeItemModel `tfsdk:"runtimes"`
+}
+
+type notebookIamConditionModel struct {
+	Title       types.String `tfsdk:"title"`
+	Description types.String `tfsdk:"description"`
+	Expression  types.String `tfsdk:"expression"`
+}
+
+type notebookIamPolicyModel struct {
+	NotebookRuntimeTemplate types.String `tfsdk:"notebook_runtime_template"`
+	PolicyData              types.String `tfsdk:"policy_data"`
+	Etag                    types.String `tfsdk:"etag"`
+}
+
+type notebookIamBindingModel struct {
+	NotebookRuntimeTemplate types.String               `tfsdk:"notebook_runtime_template"`
+	Role                    types.String               `tfsdk:"role"`
+	Members                 types.Set                  `tfsdk:"members"`
+	Condition               *notebookIamConditionModel `tfsdk:"condition"`
+	Etag                    types.String               `tfsdk:"etag"`
+}
+
+type notebookIamMemberModel struct {
+	NotebookRuntimeTemplate types.String               `tfsdk:"notebook_runtime_template"`
+	Role                    types.String               `tfsdk:"role"`
+	Member                  types.String               `tfsdk:"member"`
+	Condition               *notebookIamConditionModel `tfsdk:"condition"`
+	Etag                    types.String               `tfsdk:"etag"`
+}
+
+type notebookGcsNotebookSourceModel struct {
+	Uri        types.String `tfsdk:"uri"`
+	Generation types.String `tfsdk:"generation"`
+}
+
+type notebookDataformRepositorySourceModel struct {
+	DataformRepositoryResourceName types.String `tfsdk:"dataform_repository_resource_name"`
+	CommitSha                      types.String `tfsdk:"commit_sha"`
+}
+
+// notebookExecutionJobSpecModel are the arguments of an execution job
+type notebookExecutionJobSpecModel struct {
+	DisplayName              types.String                           `tfsdk:"display_name"`
+	NotebookRuntimeTemplate  types.String                           `tfsdk:"notebook_runtime_template"`
+	GcsNotebookSource        *notebookGcsNotebookSourceModel        `tfsdk:"gcs_notebook_source"`
+	DataformRepositorySource *notebookDataformRepositorySourceModel `tfsdk:"dataform_repository_source"`
+	GcsOutputUri             types.String                           `tfsdk:"gcs_output_uri"`
+	ExecutionUser            types.String                           `tfsdk:"execution_user"`
+	ServiceAccount           types.String                           `tfsdk:"service_account"`
+	ExecutionTimeout         types.String                           `tfsdk:"execution_timeout"`
+	Labels                   types.Map                              `tfsdk:"labels"`
+}
+
+type notebookExecutionJobModel struct {
+	Name                     types.String                           `tfsdk:"name"`
+	Project                  types.String                           `tfsdk:"project"`
+	Location                 types.String                           `tfsdk:"location"`
+	DisplayName              types.String                           `tfsdk:"display_name"`
+	NotebookRuntimeTemplate  types.String                           `tfsdk:"notebook_runtime_template"`
+	GcsNotebookSource        *notebookGcsNotebookSourceModel        `tfsdk:"gcs_notebook_source"`
+	DataformRepositorySource *notebookDataformRepositorySourceModel `tfsdk:"dataform_repository_source"`
+	GcsOutputUri             types.String                           `tfsdk:"gcs_output_uri"`
+	ExecutionUser            types.String                           `tfsdk:"execution_user"`
+	ServiceAccount           types.String                           `tfsdk:"service_account"`
+	ExecutionTimeout         types.String                           `tfsdk:"execution_timeout"`
+	Labels                   types.Map                              `tfsdk:"labels"`
+	JobState                 types.String                           `tfsdk:"job_state"`
+	StatusMessage            types.String                           `tfsdk:"status_message"`
+	OutputUri                types.String                           `tfsdk:"output_uri"`
+	CreateTime               types.String                           `tfsdk:"create_time"`
+	UpdateTime               types.String                           `tfsdk:"update_time"`
+}
+
+type notebookScheduleModel struct {
+	Name                  types.String                   `tfsdk:"name"`
+	Project               types.String                   `tfsdk:"project"`
+	Location              types.String                   `tfsdk:"location"`
+	DisplayName           types.String                   `tfsdk:"display_name"`
+	Cron                  types.String                   `tfsdk:"cron"`
+	StartTime             types.String                   `tfsdk:"start_time"`
+	EndTime               types.String                   `tfsdk:"end_time"`
+	MaxRunCount           types.Int64                    `tfsdk:"max_run_count"`
+	MaxConcurrentRunCount types.Int64                    `tfsdk:"max_concurrent_run_count"`
+	AllowQueueing         types.Bool                     `tfsdk:"allow_queueing"`
+	Paused                types.Bool                     `tfsdk:"paused"`
+	NotebookExecutionJob  *notebookExecutionJobSpecModel `tfsdk:"notebook_execution_job"`
+	State                 types.String                   `tfsdk:"state"`
+	StartedRunCount       types.Int64                    `tfsdk:"started_run_count"`
+	NextRunTime           types.String                   `tfsdk:"next_run_time"`
+	CreateTime            types.String                   `tfsdk:"create_time"`
+	UpdateTime            types.String                   `tfsdk:"update_time"`
+}
+
+type workbenchAcceleratorConfigModel struct {
+	Type      types.String `tfsdk:"type"`
+	CoreCount types.Int64  `tfsdk:"core_count"`
+}
+
+type workbenchDiskModel struct {
+	DiskSizeGb types.Int64  `tfsdk:"disk_size_gb"`
+	DiskType   types.String `tfsdk:"disk_type"`
+}
+
+type workbenchNetworkInterfaceModel struct {
+	Network types.String `tfsdk:"network"`
+	Subnet  types.String `tfsdk:"subnet"`
+	NicType types.String `tfsdk:"nic_type"`
+}
+
+type workbenchShieldedInstanceConfigModel struct {
+	EnableSecureBoot          types.Bool `tfsdk:"enable_secure_boot"`
+	EnableVtpm                types.Bool `tfsdk:"enable_vtpm"`
+	EnableIntegrityMonitoring types.Bool `tfsdk:"enable_integrity_monitoring"`
+}
+
+type workbenchInstanceModel struct {
+	Name                   types.String                          `tfsdk:"name"`
+	InstanceId             types.String                          `tfsdk:"instance_id"`
+	Project                types.String                          `tfsdk:"project"`
+	Zone                   types.String                          `tfsdk:"zone"`
+	MachineType            types.String                          `tfsdk:"machine_type"`
+	AcceleratorConfig      *workbenchAcceleratorConfigModel      `tfsdk:"accelerator_config"`
+	BootDisk               *workbenchDiskModel                   `tfsdk:"boot_disk"`
+	DataDisk               *workbenchDiskModel                   `tfsdk:"data_disk"`
+	KmsKey                 types.String                          `tfsdk:"kms_key"`
+	NetworkInterface       *workbenchNetworkInterfaceModel       `tfsdk:"network_interface"`
+	DisablePublicIp        types.Bool                            `tfsdk:"disable_public_ip"`
+	ServiceAccount         types.String                          `tfsdk:"service_account"`
+	Metadata               types.Map                             `tfsdk:"metadata"`
+	ShieldedInstanceConfig *workbenchShieldedInstanceConfigModel `tfsdk:"shielded_instance_config"`
+	Labels                 types.Map                             `tfsdk:"labels"`
+	DesiredState           types.String                          `tfsdk:"desired_state"`
+	State                  types.String                          `tfsdk:"state"`
+	ProxyUri               types.String                          `tfsdk:"proxy_uri"`
+	Creator                types.String                          `tfsdk:"creator"`
+	CreateTime             types.String                          `tfsdk:"create_time"`
+	UpdateTime             types.String                          `tfsdk:"update_time"`
+}
+
+type notebookContentModel struct {
+	Name          types.String `tfsdk:"name"`
+	RepositoryId  types.String `tfsdk:"repository_id"`
+	Project       types.String `tfsdk:"project"`
+	Location      types.String `tfsdk:"location"`
+	DisplayName   types.String `tfsdk:"display_name"`
+	Labels        types.Map    `tfsdk:"labels"`
+	Source        types.String `tfsdk:"source"`
+	Path          types.String `tfsdk:"path"`
+	AuthorName    types.String `tfsdk:"author_name"`
+	AuthorEmail   types.String `tfsdk:"author_email"`
+	CommitMessage types.String `tfsdk:"commit_message"`
+	ContentSha256 types.String `tfsdk:"content_sha256"`
+	CommitSha     types.String `tfsdk:"commit_sha"`
+	CreateTime    types.String `tfsdk:"create_time"`
+}
+
+type machineTypeItemModel struct {
+	Name             types.String `tfsdk:"name"`
+	Family           types.String `tfsdk:"family"`
+	GuestCpus        types.Int64  `tfsdk:"guest_cpus"`
+	MemoryMb         types.Int64  `tfsdk:"memory_mb"`
+	IsSharedCpu      types.Bool   `tfsdk:"is_shared_cpu"`
+	AcceleratorType  types.String `tfsdk:"accelerator_type"`
+	AcceleratorCount types.Int64  `tfsdk:"accelerator_count"`
+	Zones            types.List   `tfsdk:"zones"`
+}
+
+type acceleratorTypeItemModel struct {
+	Name         types.String `tfsdk:"name"`
+	ComputeName  types.String `tfsdk:"compute_name"`
+	Description  types.String `tfsdk:"description"`
+	MaximumCount types.Int64  `tfsdk:"maximum_count"`
+	Zones        types.List   `tfsdk:"zones"`
+}
+
+type machineTypesDataSourceModel struct {
+	Project          types.String               `tfsdk:"project"`
+	Location         types.String               `tfsdk:"location"`
+	MachineTypes     []machineTypeItemModel     `tfsdk:"machine_types"`
+	AcceleratorTypes []acceleratorTypeItemModel `tfsdk:"accelerator_types"`
+}
+
+type acceleratorCompatibilityModel struct {
+	MachineFamilies types.List `tfsdk:"machine_families"`
+	Counts          types.List `tfsdk:"counts"`
+}
+
+type guardrailsModel struct {
+	RequireKmsKey           types.Bool  `tfsdk:"require_kms_key"`
+	AllowedMachineTypes     types.List  `tfsdk:"allowed_machine_types"`
+	MaxAcceleratorCount     types.Int64 `tfsdk:"max_accelerator_count"`
+	AllowedAcceleratorTypes types.List  `tfsdk:"allowed_accelerator_types"`
+	ForbidInternetAccess    types.Bool  `tfsdk:"forbid_internet_access"`
+	RequiredLabels          types.List  `tfsdk:"required_labels"`
+	MaxDiskSizeGb           types.Int64 `tfsdk:"max_disk_size_gb"`
+	RequireIdleShutdown     types.Bool  `tfsdk:"require_idle_shutdown"`
+}
+
+// valueOrZero dereferences an optional nested model, the zero value of a
+// model holds null attributes
+func valueOrZero[T any](model *T) T {
+
+	if model == nil {
+		var zero T
+		return zero
+	}
+	return *model
 }
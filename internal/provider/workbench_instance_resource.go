@@ -0,0 +1,726 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &workbenchInstanceResource{}
+	_ resource.ResourceWithConfigure      = &workbenchInstanceResource{}
+	_ resource.ResourceWithValidateConfig = &workbenchInstanceResource{}
+)
+
+// just making alias to not get confused
+type workbenchInstanceResource gcpNotebookClient
+
+func NewWorkbenchInstanceResource() resource.Resource {
+	return &workbenchInstanceResource{}
+}
+
+func (w *workbenchInstanceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T", req.ProviderData),
+		)
+
+		return
+	}
+
+	w.provider = data
+}
+
+// Metadata implements resource.Resource.
+func (w *workbenchInstanceResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workbench_instance"
+}
+
+func (w *workbenchInstanceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+
+	var data workbenchInstanceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.DesiredState.IsNull() && !data.DesiredState.IsUnknown() {
+		if state := data.DesiredState.ValueString(); state != gcp.InstanceStateActive && state != gcp.InstanceStateStopped {
+			resp.Diagnostics.AddAttributeError(
+				tfpath.Root("desired_state"),
+				"desired_state must be either ACTIVE or STOPPED",
+				fmt.Sprintf("Expected desired_state to be ACTIVE or STOPPED, got: %s", state),
+			)
+		}
+	}
+
+	for attribute, disk := range map[string]*workbenchDiskModel{"boot_disk": data.BootDisk, "data_disk": data.DataDisk} {
+		if disk == nil || disk.DiskSizeGb.IsNull() || disk.DiskSizeGb.IsUnknown() {
+			continue
+		}
+		if disk.DiskSizeGb.ValueInt64() < 10 {
+			resp.Diagnostics.AddAttributeError(
+				tfpath.Root(attribute).AtName("disk_size_gb"),
+				"disk_size_gb must be at least 10",
+				fmt.Sprintf("Expected disk_size_gb to be at least 10, got %d", disk.DiskSizeGb.ValueInt64()),
+			)
+		}
+	}
+
+	if data.AcceleratorConfig != nil && !data.AcceleratorConfig.CoreCount.IsNull() && !data.AcceleratorConfig.CoreCount.IsUnknown() && data.AcceleratorConfig.CoreCount.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			tfpath.Root("accelerator_config").AtName("core_count"),
+			"core_count must be at least 1",
+			fmt.Sprintf("Expected core_count to be at least 1, got %d", data.AcceleratorConfig.CoreCount.ValueInt64()),
+		)
+	}
+}
+
+// Schema implements resource.Resource.
+func (w *workbenchInstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+
+	tflog.Debug(ctx, "********* In Schema(workbench_instance_resource) *********")
+
+	diskAttributes := map[string]schema.Attribute{
+		"disk_size_gb": schema.Int64Attribute{
+			Optional:            true,
+			MarkdownDescription: "The size of the disk in GB, at least 10",
+		},
+		"disk_type": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "One of `PD_STANDARD`, `PD_SSD`, `PD_BALANCED` or `PD_EXTREME`",
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A Vertex AI Workbench instance (Notebooks API v2)",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"instance_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The id of the instance, the last part of `name`",
+			},
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				MarkdownDescription: "The project to create the instance in, defaults to the provider project",
+			},
+			"zone": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The zone to create the instance in, e.g. `australia-southeast1-a`",
+			},
+			"machine_type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The machine type, e.g. `e2-standard-4`. Changing it stops the instance while it is updated",
+			},
+			"accelerator_config": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "The GPUs attached to the instance. Changing it stops the instance while it is updated",
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "The accelerator type, e.g. `NVIDIA_TESLA_T4`",
+					},
+					"core_count": schema.Int64Attribute{
+						Required: true,
+					},
+				},
+			},
+			"boot_disk": schema.SingleNestedAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: diskAttributes,
+			},
+			"data_disk": schema.SingleNestedAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: diskAttributes,
+			},
+			"kms_key": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The KMS key encrypting the boot and data disks (CMEK), Google managed when not set",
+			},
+			"network_interface": schema.SingleNestedAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"network": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "The VPC network, e.g. `projects/my-project/global/networks/default`",
+					},
+					"subnet": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "The subnetwork, e.g. `projects/my-project/regions/australia-southeast1/subnetworks/default`",
+					},
+					"nic_type": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Either `VIRTIO_NET` or `GVNIC`",
+					},
+				},
+			},
+			"disable_public_ip": schema.BoolAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"service_account": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "The email of the service account the instance runs as, the Compute Engine default when not set",
+			},
+			"metadata": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Custom metadata for the instance, metadata added by Workbench itself is ignored",
+			},
+			"shielded_instance_config": schema.SingleNestedAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"enable_secure_boot": schema.BoolAttribute{
+						Optional: true,
+					},
+					"enable_vtpm": schema.BoolAttribute{
+						Optional: true,
+					},
+					"enable_integrity_monitoring": schema.BoolAttribute{
+						Optional: true,
+					},
+				},
+			},
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"desired_state": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(gcp.InstanceStateActive),
+				MarkdownDescription: "Either `ACTIVE` (default) or `STOPPED`, the instance is started or stopped in place",
+			},
+			"state": schema.StringAttribute{
+				Computed: true,
+			},
+			"proxy_uri": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"creator": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"create_time": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"update_time": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Create implements resource.Resource.
+func (w *workbenchInstanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+
+	tflog.Debug(ctx, "********* In Create(workbench_instance_resource) *********")
+
+	var plan workbenchInstanceModel
+	diags := req.Plan.Get(ctx, &plan)
+
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instance, diags := plan.toInstance(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, _ := w.provider.projectLocation(plan.Project, types.StringNull())
+	plan.Project = types.StringValue(project)
+
+	client := w.provider.workbenchClient(project, plan.Zone.ValueString())
+
+	created, err := client.CreateInstance(ctx, plan.InstanceId.ValueString(), instance)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating instance",
+			"Could not create Workbench instance, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// save what we have so a failure below doesn't orphan the instance,
+	// a newly created instance is active
+	desiredState := plan.DesiredState
+	plan.Name = types.StringPointerValue(created.Name)
+	plan.DesiredState = types.StringValue(gcp.InstanceStateActive)
+	plan.refresh(created)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created, err = w.applyDesiredState(ctx, client, plan.Name.ValueString(), desiredState.ValueString(), plan.DesiredState)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error stopping instance",
+			"Could not stop Workbench instance, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.DesiredState = desiredState
+	plan.refresh(created)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read implements resource.Resource.
+func (w *workbenchInstanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+
+	tflog.Debug(ctx, "********* In Read(workbench_instance_resource) *********")
+
+	var state workbenchInstanceModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, _ := w.provider.projectLocation(state.Project, types.StringNull())
+
+	instance, err := w.provider.workbenchClient(project, state.Zone.ValueString()).GetInstance(state.Name.ValueString())
+
+	if gcp.IsNotFound(err) {
+		tflog.Warn(ctx, "Workbench instance no longer exists, removing from state", map[string]interface{}{"name": state.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading GCP Workbench Instance",
+			"Could not read Workbench instance with name "+state.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Project = types.StringValue(project)
+
+	if setup := instance.GceSetup; setup != nil {
+
+		// the API answers with the machine type url
+		if setup.MachineType != nil {
+			state.MachineType = types.StringValue(path.Base(*setup.MachineType))
+		}
+
+		state.AcceleratorConfig = nil
+		if len(setup.AcceleratorConfigs) > 0 {
+			state.AcceleratorConfig = &workbenchAcceleratorConfigModel{
+				Type:      types.StringPointerValue(setup.AcceleratorConfigs[0].Type),
+				CoreCount: types.Int64PointerValue(setup.AcceleratorConfigs[0].CoreCount),
+			}
+		}
+
+		// disks are only refreshed when configured, the API fills in defaults
+		if state.BootDisk != nil && setup.BootDisk != nil {
+			state.BootDisk.refresh(setup.BootDisk)
+		}
+		if state.DataDisk != nil && len(setup.DataDisks) > 0 {
+			state.DataDisk.refresh(&setup.DataDisks[0])
+		}
+
+		if !state.ServiceAccount.IsNull() && len(setup.ServiceAccounts) > 0 {
+			state.ServiceAccount = types.StringPointerValue(setup.ServiceAccounts[0].Email)
+		}
+
+		// Workbench adds its own metadata, only keep track of the keys we set
+		var metadata map[string]string
+		if setup.Metadata != nil {
+			metadata = *setup.Metadata
+		}
+		state.Metadata, diags = refreshLabels(ctx, state.Metadata, metadata)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	var labels map[string]string
+	if instance.Labels != nil {
+		labels = *instance.Labels
+	}
+	state.Labels, diags = refreshLabels(ctx, state.Labels, labels)
+	resp.Diagnostics.Append(diags...)
+
+	state.refresh(instance)
+
+	// surface an instance started or stopped outside of Terraform as a
+	// change to desired_state
+	if instanceState := stringOrEmpty(instance.State); instanceState == gcp.InstanceStateActive || instanceState == gcp.InstanceStateStopped {
+		state.DesiredState = types.StringValue(instanceState)
+	}
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update implements resource.Resource.
+func (w *workbenchInstanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+
+	tflog.Debug(ctx, "********* In Update(workbench_instance_resource) *********")
+
+	var plan, state workbenchInstanceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, diags := plan.toInstance(ctx)
+	resp.Diagnostics.Append(diags...)
+	current, diags := state.toInstance(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, _ := w.provider.projectLocation(plan.Project, types.StringNull())
+	client := w.provider.workbenchClient(project, plan.Zone.ValueString())
+	name := state.Name.ValueString()
+
+	var updateMask []string
+	stopRequired := false
+
+	if planned.GceSetup.MachineType != nil && *planned.GceSetup.MachineType != *current.GceSetup.MachineType {
+		updateMask = append(updateMask, "gce_setup.machine_type")
+		stopRequired = true
+	}
+	if !reflect.DeepEqual(planned.GceSetup.AcceleratorConfigs, current.GceSetup.AcceleratorConfigs) {
+		updateMask = append(updateMask, "gce_setup.accelerator_configs")
+		stopRequired = true
+	}
+	updateMetadata := !reflect.DeepEqual(planned.GceSetup.Metadata, current.GceSetup.Metadata)
+	updateLabels := !reflect.DeepEqual(planned.Labels, current.Labels)
+
+	// both maps are replaced as a whole, keys added by Workbench or outside of
+	// Terraform are kept by merging the configured keys into the live ones
+	if updateMetadata || updateLabels {
+
+		live, err := client.GetInstance(name)
+
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating instance",
+				"Could not read Workbench instance before updating it, unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		if updateMetadata {
+			var liveMetadata map[string]string
+			if live.GceSetup != nil && live.GceSetup.Metadata != nil {
+				liveMetadata = *live.GceSetup.Metadata
+			}
+			metadata := mergeConfiguredKeys(liveMetadata, current.GceSetup.Metadata, planned.GceSetup.Metadata)
+			planned.GceSetup.Metadata = &metadata
+			updateMask = append(updateMask, "gce_setup.metadata")
+		}
+
+		if updateLabels {
+			var liveLabels map[string]string
+			if live.Labels != nil {
+				liveLabels = *live.Labels
+			}
+			labels := mergeConfiguredKeys(liveLabels, current.Labels, planned.Labels)
+			planned.Labels = &labels
+			updateMask = append(updateMask, "labels")
+		}
+	}
+
+	currentState := state.DesiredState
+
+	// machine type and GPUs can only be changed on a stopped instance
+	if stopRequired && currentState.ValueString() == gcp.InstanceStateActive {
+
+		tflog.Info(ctx, "Stopping Workbench instance to update it", map[string]interface{}{"name": name})
+
+		if err := client.StopInstance(ctx, name); err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating instance",
+				"Could not stop Workbench instance before updating it, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		currentState = types.StringValue(gcp.InstanceStateStopped)
+	}
+
+	if len(updateMask) > 0 {
+
+		planned.Name = &name
+
+		if _, err := client.UpdateInstance(ctx, planned, updateMask); err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating instance",
+				"Could not update Workbench instance, unexpected error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	instance, err := w.applyDesiredState(ctx, client, name, plan.DesiredState.ValueString(), currentState)
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating instance",
+			"Could not change the Workbench instance state, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.refresh(instance)
+
+	// Set state to fully populated data
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete implements resource.Resource.
+func (w *workbenchInstanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+
+	tflog.Debug(ctx, "********* In Delete(workbench_instance_resource) *********")
+
+	var state workbenchInstanceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, _ := w.provider.projectLocation(state.Project, types.StringNull())
+
+	err := w.provider.workbenchClient(project, state.Zone.ValueString()).DeleteInstance(ctx, state.Name.ValueString())
+
+	if err != nil && !gcp.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting instance",
+			"Could not delete Workbench instance, unexpected error: "+err.Error(),
+		)
+	}
+}
+
+// applyDesiredState starts or stops the instance when desired differs from
+// current, returning the refreshed instance
+func (w *workbenchInstanceResource) applyDesiredState(ctx context.Context, client *gcp.WorkbenchClient, name string, desired string, current types.String) (*gcp.WorkbenchInstance, error) {
+
+	var err error
+
+	if desired != current.ValueString() {
+		switch desired {
+		case gcp.InstanceStateActive:
+			err = client.StartInstance(ctx, name)
+		case gcp.InstanceStateStopped:
+			err = client.StopInstance(ctx, name)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return client.GetInstance(name)
+}
+
+// refresh copies the server-side state of the instance into the model
+func (m *workbenchInstanceModel) refresh(instance *gcp.WorkbenchInstance) {
+
+	m.State = types.StringPointerValue(instance.State)
+	m.ProxyUri = types.StringPointerValue(instance.ProxyUri)
+	m.Creator = types.StringPointerValue(instance.Creator)
+	m.CreateTime = types.StringPointerValue(instance.CreateTime)
+	m.UpdateTime = types.StringPointerValue(instance.UpdateTime)
+}
+
+func (m *workbenchDiskModel) refresh(disk *gcp.WorkbenchDisk) {
+
+	if !m.DiskSizeGb.IsNull() {
+		m.DiskSizeGb = types.Int64PointerValue(disk.DiskSizeGb)
+	}
+	if !m.DiskType.IsNull() {
+		m.DiskType = types.StringPointerValue(disk.DiskType)
+	}
+}
+
+// toDisk converts the disk, encrypted with kmsKey when set
+func (m *workbenchDiskModel) toDisk(kmsKey types.String) *gcp.WorkbenchDisk {
+
+	disk := &gcp.WorkbenchDisk{}
+
+	if m != nil {
+		disk.DiskSizeGb = m.DiskSizeGb.ValueInt64Pointer()
+		disk.DiskType = m.DiskType.ValueStringPointer()
+	}
+
+	if !kmsKey.IsNull() {
+		encryption := "CMEK"
+		disk.DiskEncryption = &encryption
+		disk.KmsKey = kmsKey.ValueStringPointer()
+	}
+	return disk
+}
+
+// toInstance converts the arguments into the API representation
+func (m workbenchInstanceModel) toInstance(ctx context.Context) (*gcp.WorkbenchInstance, diag.Diagnostics) {
+
+	var diags diag.Diagnostics
+
+	setup := &gcp.GceSetup{
+		MachineType:     m.MachineType.ValueStringPointer(),
+		DisablePublicIp: m.DisablePublicIp.ValueBoolPointer(),
+	}
+
+	if m.AcceleratorConfig != nil {
+		setup.AcceleratorConfigs = []gcp.AcceleratorConfig{{
+			Type:      m.AcceleratorConfig.Type.ValueStringPointer(),
+			CoreCount: m.AcceleratorConfig.CoreCount.ValueInt64Pointer(),
+		}}
+	}
+
+	if m.BootDisk != nil || !m.KmsKey.IsNull() {
+		setup.BootDisk = m.BootDisk.toDisk(m.KmsKey)
+	}
+
+	if m.DataDisk != nil {
+		setup.DataDisks = []gcp.WorkbenchDisk{*m.DataDisk.toDisk(m.KmsKey)}
+	}
+
+	if m.NetworkInterface != nil {
+		setup.NetworkInterfaces = []gcp.WorkbenchNetworkConfig{{
+			Network: m.NetworkInterface.Network.ValueStringPointer(),
+			Subnet:  m.NetworkInterface.Subnet.ValueStringPointer(),
+			NicType: m.NetworkInterface.NicType.ValueStringPointer(),
+		}}
+	}
+
+	if !m.ServiceAccount.IsNull() {
+		setup.ServiceAccounts = []gcp.ServiceAccount{{
+			Email: m.ServiceAccount.ValueStringPointer(),
+		}}
+	}
+
+	if m.ShieldedInstanceConfig != nil {
+		setup.ShieldedInstanceConfig = &gcp.ShieldedInstanceConfig{
+			EnableSecureBoot:          m.ShieldedInstanceConfig.EnableSecureBoot.ValueBoolPointer(),
+			EnableVtpm:                m.ShieldedInstanceConfig.EnableVtpm.ValueBoolPointer(),
+			EnableIntegrityMonitoring: m.ShieldedInstanceConfig.EnableIntegrityMonitoring.ValueBoolPointer(),
+		}
+	}
+
+	metadata, d := labelsFromValue(ctx, m.Metadata)
+	diags.Append(d...)
+	if len(metadata) > 0 {
+		setup.Metadata = &metadata
+	}
+
+	instance := &gcp.WorkbenchInstance{GceSetup: setup}
+
+	labels, d := labelsFromValue(ctx, m.Labels)
+	diags.Append(d...)
+	if len(labels) > 0 {
+		instance.Labels = &labels
+	}
+
+	return instance, diags
+}
+
+// mergeConfiguredKeys overlays the planned keys on the live ones, removing
+// only keys that were configured before and no longer are
+func mergeConfiguredKeys(live map[string]string, prior *map[string]string, planned *map[string]string) map[string]string {
+
+	merged := make(map[string]string, len(live))
+	for k, v := range live {
+		merged[k] = v
+	}
+	if prior != nil {
+		for k := range *prior {
+			delete(merged, k)
+		}
+	}
+	if planned != nil {
+		for k, v := range *planned {
+			merged[k] = v
+		}
+	}
+	return merged
+}
@@ -6,8 +6,14 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"regexp"
+	"strings"
 
+	"github.com/mpstella/terraform-provider-daw/internal/export"
+	"github.com/mpstella/terraform-provider-daw/internal/gcp"
 	"github.com/mpstella/terraform-provider-daw/internal/provider"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -33,6 +39,14 @@ var (
 )
 
 func main() {
+
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -52,3 +66,74 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// labelFlags collects repeated -label key=value flags
+type labelFlags map[string]string
+
+func (l labelFlags) String() string {
+	return fmt.Sprint(map[string]string(l))
+}
+
+func (l labelFlags) Set(value string) error {
+
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got: %s", value)
+	}
+	l[key] = val
+	return nil
+}
+
+// runExport writes the runtime templates of a project and location out as
+// daw_notebook resources with import blocks, one file per template
+//
+//	terraform-provider-daw export -project my-project -location us-central1 -label team=data -out imported
+func runExport(args []string) error {
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+
+	project := flags.String("project", os.Getenv("CLOUDSDK_CORE_PROJECT"), "the project to export templates from")
+	location := flags.String("location", os.Getenv("CLOUDSDK_COMPUTE_REGION"), "the location to export templates from")
+	out := flags.String("out", "daw-export", "the directory to write the .tf files to, existing files are never overwritten")
+	name := flags.String("name", "", "only export templates whose display name or id matches this regular expression")
+
+	labels := labelFlags{}
+	flags.Var(labels, "label", "only export templates with this key=value label, can be repeated")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *project == "" || *location == "" {
+		return fmt.Errorf("both -project and -location are required")
+	}
+
+	opts := export.Options{
+		OutputDir: *out,
+		Labels:    labels,
+	}
+
+	if *name != "" {
+		nameRegexp, err := regexp.Compile(*name)
+		if err != nil {
+			return fmt.Errorf("invalid -name: %w", err)
+		}
+		opts.NameRegexp = nameRegexp
+	}
+
+	// the same environment variables the provider honours
+	config := gcp.ConfigFromEnv()
+	config.UserAgent = fmt.Sprintf("terraform-provider-daw/%s (export)", version)
+
+	factory, err := gcp.NewClientFactory(config)
+	if err != nil {
+		return err
+	}
+
+	files, err := export.Run(factory.NotebookClient(*project, *location), opts)
+
+	for _, file := range files {
+		fmt.Println(file)
+	}
+	return err
+}